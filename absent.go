@@ -0,0 +1,70 @@
+package got
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/fatih/structtag"
+)
+
+// checkAbsent ensures that the fields of actual whose golden file is absent
+// from dir are empty, as used by Options.AbsentIsEmpty.
+func checkAbsent(dir string, actual any) error {
+	var failures []string
+
+	check := func(name, file string, value reflect.Value) {
+		if value.IsZero() {
+			return
+		}
+
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			failures = append(failures, fmt.Sprintf("%s: golden file %q does not exist, so the value is expected to be empty (use -update-golden to create it)", name, file))
+		}
+	}
+
+	err := walkDirFields(dir, actual, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		if isRemote(tag.Name) {
+			return nil
+		}
+
+		name := fmt.Sprintf("%s.%s", getTypeName(actual), fieldName(field, tag))
+
+		if isExplodeSlice(field.Type, tag) {
+			files, err := explodeSliceFiles(tag.Name, value.Len())
+			if err != nil {
+				return err
+			}
+
+			for i, file := range files {
+				check(fmt.Sprintf("%s[%d]", name, i), filepath.Join(dir, file), value.Index(i))
+			}
+
+			return nil
+		}
+
+		if isMap(field.Type) && tag.HasOption("explode") {
+			for _, key := range value.MapKeys() {
+				file := explodeFile(dir, tag, key.String())
+				check(fmt.Sprintf("%s[%q]", name, key.String()), file, value.MapIndex(key))
+			}
+
+			return nil
+		}
+
+		check(name, platformFile(osFS, dir, alternateName(osFS, dir, testdataFile(field, tag, value))), value)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "\n"))
+	}
+
+	return nil
+}
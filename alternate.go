@@ -0,0 +1,33 @@
+package got
+
+import (
+	"strings"
+)
+
+// A file name in a struct tag can list alternatives separated by "|" (eg:
+// `testdata:"input.json|input.yaml"`), where the first one that exists is used
+// and the codec is chosen by that file's extension.
+const alternateSeparator = "|"
+
+// alternateName returns the first alternative in name which exists within
+// dir, falling back to the first alternative when none of them exist.
+func alternateName(fsys fileSystem, dir, name string) string {
+	if !strings.Contains(name, alternateSeparator) {
+		return name
+	}
+
+	alternates := strings.Split(name, alternateSeparator)
+	for _, alternate := range alternates {
+		if _, err := fsys.Stat(platformFile(fsys, dir, alternate)); err == nil {
+			return alternate
+		}
+	}
+
+	return alternates[0]
+}
+
+// firstAlternate returns the first alternative in name, which is used when
+// there is no dir to check the alternatives against.
+func firstAlternate(name string) string {
+	return strings.SplitN(name, alternateSeparator, 2)[0]
+}
@@ -0,0 +1,65 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlternateFiles(t *testing.T) {
+	type user struct {
+		Name string `json:"name" yaml:"name"`
+	}
+
+	type test struct {
+		Input user `testdata:"input.json|input.yaml"`
+	}
+
+	t.Run("load", func(t *testing.T) {
+		testLoadOne(t, "alternate", new(test), &test{Input: user{Name: "alice"}}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/alternate/input.yaml" as YAML (size 12)`,
+		})
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "testdata/alternate", &test{Input: user{Name: "alice"}})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("save existing", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "input.yaml"), []byte("name: bob\n"), 0644))
+
+		var mt mockT
+		Assert(&mt, dir, &test{Input: user{Name: "alice"}})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "input.yaml"))
+		require.NoError(t, err)
+		require.Equal(t, "name: alice\n", string(data))
+
+		_, err = os.Stat(filepath.Join(dir, "input.json"))
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("save new", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		Assert(&mt, dir, &test{Input: user{Name: "alice"}})
+		require.False(t, mt.failed, mt.logs)
+
+		_, err := os.Stat(filepath.Join(dir, "input.json"))
+		require.NoError(t, err)
+	})
+}
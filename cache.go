@@ -0,0 +1,154 @@
+package got
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// fileCache holds the contents of the files read while Options.CacheFiles is
+// enabled, which is shared by every call in the process (ie: across test
+// cases) so that a large fixture in a SharedDir is only read once.
+var fileCache = struct {
+	sync.Mutex
+	files map[string]cachedFile
+}{
+	files: make(map[string]cachedFile),
+}
+
+// cachedFile is the contents of a file, along with the modification time and
+// size used to detect when the file has changed since it was cached. The
+// contents are kept as a string since it is immutable, so it can be shared by
+// string fields while everything else decodes from a copy.
+type cachedFile struct {
+	modTime time.Time
+	size    int64
+	text    string
+}
+
+// readTagFile returns the contents of file, or false when it does not exist.
+// When CacheFiles is enabled, the contents are reused from the cache while the
+// file is unchanged, but each caller receives a copy so that nothing decoded
+// (or formatted) from them can be shared between test cases.
+func (o Options) readTagFile(file string, timing *fileTiming) ([]byte, bool, error) {
+	if !o.CacheFiles || o.fsys != nil {
+		return o.readTagFileUncached(file, timing)
+	}
+
+	text, found, err := o.readCachedFile(file, timing)
+	if err != nil || !found {
+		return nil, found, err
+	}
+
+	return []byte(text), true, nil
+}
+
+// readCachedFile returns the contents of file from the cache, reading (and
+// caching) it when it is missing or has changed since it was cached.
+func (o Options) readCachedFile(file string, timing *fileTiming) (string, bool, error) {
+	info, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+
+	key, err := filepath.Abs(file)
+	if err != nil {
+		return "", false, err
+	}
+
+	fileCache.Lock()
+	cached, ok := fileCache.files[key]
+	fileCache.Unlock()
+
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.text, true, nil
+	}
+
+	data, found, err := o.readTagFileUncached(file, timing)
+	if err != nil || !found {
+		return "", found, err
+	}
+
+	// files truncated by MaxFileSize are not cached, since they would be
+	// incomplete for calls which use a larger limit
+	if int64(len(data)) != info.Size() {
+		return string(data), true, nil
+	}
+
+	cached = cachedFile{modTime: info.ModTime(), size: info.Size(), text: string(data)}
+
+	fileCache.Lock()
+	fileCache.files[key] = cached
+	fileCache.Unlock()
+
+	return cached.text, true, nil
+}
+
+func (o Options) readTagFileUncached(file string, timing *fileTiming) ([]byte, bool, error) {
+	start := time.Now()
+
+	f, err := openTagFile(o.files(), file)
+	if err != nil {
+		return nil, false, err
+	} else if f == nil {
+		return nil, false, nil
+	}
+	defer f.Close()
+
+	opened := time.Now()
+	timing.setOpen(opened.Sub(start))
+
+	data, err := readFile(f, o.MaxFileSize)
+	if err != nil {
+		return nil, false, fmt.Errorf("file %q read error: %w", file, err)
+	}
+
+	timing.setRead(time.Since(opened))
+
+	return data, true, nil
+}
+
+// canShareCached reports whether value can be set to the cached contents of
+// file directly, which is only the case for strings loaded as-is.
+func (opts fileOptions) canShareCached(file string, value reflect.Value) bool {
+	if !isString(value.Type()) {
+		return false
+	}
+
+	return !formatters.has(file) && opts.pipeline == (pipeline{}) && opts.part == "" && opts.concat == nil && opts.include == nil
+}
+
+// loadCachedString is the same as loadFile for a string field which can share
+// the cached contents of file (see canShareCached).
+func (o Options) loadCachedString(log *logger, file string, opts fileOptions, value reflect.Value, timing *fileTiming) error {
+	text, found, err := o.readCachedFile(file, timing)
+	if err != nil {
+		return err
+	} else if !found {
+		log.Missing("skipped: file %q not found", file)
+		return nil
+	}
+
+	if o.MaxFileSize > 0 && int64(len(text)) > o.MaxFileSize {
+		return fmt.Errorf("file %q exceeds max file size of %d bytes", file, o.MaxFileSize)
+	}
+
+	if opts.nonempty && len(text) == 0 {
+		return fmt.Errorf("file %q is empty, but the nonempty option requires contents", file)
+	}
+
+	value.SetString(text)
+	log.Log("loaded file %q as string (size %d)", file, len(text))
+
+	if opts.resolveDir != "" {
+		resolvePaths(opts.resolveDir, value)
+	}
+
+	timing.log(log, file)
+	return nil
+}
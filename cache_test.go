@@ -0,0 +1,99 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheFiles(t *testing.T) {
+	type test struct {
+		Raw   []byte            `testdata:"raw.txt"`
+		Text  string            `testdata:"raw.txt"`
+		Items []string          `testdata:"items.json"`
+		Attrs map[string]string `testdata:"attrs.json"`
+	}
+
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "raw.txt"), []byte("hello"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "items.json"), []byte(`["a","b"]`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "attrs.json"), []byte(`{"a":"1"}`), 0644))
+		return dir
+	}
+
+	opts := Options{CacheFiles: true}
+
+	t.Run("isolated between loads", func(t *testing.T) {
+		dir := setup(t)
+
+		var mt mockT
+		var first, second test
+		opts.Load(&mt, dir, &first)
+		require.False(t, mt.failed, mt.logs)
+
+		first.Raw[0] = 'j'
+		first.Items[0] = "changed"
+		first.Attrs["a"] = "changed"
+
+		opts.Load(&mt, dir, &second)
+		require.False(t, mt.failed, mt.logs)
+
+		expected := test{
+			Raw:   []byte("hello"),
+			Text:  "hello",
+			Items: []string{"a", "b"},
+			Attrs: map[string]string{"a": "1"},
+		}
+		require.Equal(t, expected, second)
+	})
+
+	t.Run("changed file", func(t *testing.T) {
+		dir := setup(t)
+
+		var mt mockT
+		var first, second test
+		opts.Load(&mt, dir, &first)
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "hello", string(first.Raw))
+
+		file := filepath.Join(dir, "raw.txt")
+		require.NoError(t, os.WriteFile(file, []byte("world"), 0644))
+		modTime := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(file, modTime, modTime))
+
+		opts.Load(&mt, dir, &second)
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "world", string(second.Raw))
+		require.Equal(t, "world", second.Text)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		dir := t.TempDir()
+
+		var mt mockT
+		var actual test
+		opts.Load(&mt, dir, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, test{}, actual)
+		require.Contains(t, mt.logs, "[GoT] Load: *got.test.Raw: skipped: file \""+filepath.Join(dir, "raw.txt")+"\" not found")
+	})
+
+	t.Run("truncated by max file size", func(t *testing.T) {
+		dir := setup(t)
+
+		var mt mockT
+		var actual test
+		Options{CacheFiles: true, MaxFileSize: 2}.Load(&mt, dir, &actual)
+		require.True(t, mt.failed)
+
+		mt = mockT{}
+		opts.Load(&mt, dir, &actual)
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "hello", string(actual.Raw))
+	})
+}
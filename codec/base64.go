@@ -0,0 +1,39 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/base64"
+)
+
+// Base64Layer encodes data as standard base64, with a trailing newline when
+// TrailingNewline is set. Surrounding whitespace is ignored when decoding.
+type Base64Layer struct {
+	TrailingNewline bool
+}
+
+func (l *Base64Layer) Name() string {
+	return "base64"
+}
+
+func (l *Base64Layer) Encode(data []byte) ([]byte, error) {
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(out, data)
+
+	if l.TrailingNewline {
+		out = append(out, '\n')
+	}
+
+	return out, nil
+}
+
+func (l *Base64Layer) Decode(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+
+	out := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+	n, err := base64.StdEncoding.Decode(out, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return out[:n], nil
+}
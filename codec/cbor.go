@@ -0,0 +1,32 @@
+package codec
+
+import (
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+var (
+	// deterministic encoding (eg: sorted map keys) keeps golden files stable
+	cborEncMode, _ = cbor.CoreDetEncOptions().EncMode()
+
+	// decode maps with string keys by default so map[string]any and any values
+	// behave the same as with the JSON and YAML codecs
+	cborDecMode, _ = cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]any(nil)),
+	}.DecMode()
+)
+
+type CBORCodec struct{}
+
+func (c *CBORCodec) Name() string {
+	return "CBOR"
+}
+
+func (c *CBORCodec) Marshal(v any) ([]byte, error) {
+	return cborEncMode.Marshal(v)
+}
+
+func (c *CBORCodec) Unmarshal(data []byte, v any) error {
+	return cborDecMode.Unmarshal(data, v)
+}
@@ -0,0 +1,49 @@
+package codec
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCBORCodec(t *testing.T) {
+	type s struct {
+		String  string `cbor:"string,omitempty"`
+		Integer int    `cbor:"integer,omitempty"`
+		Boolean bool   `cbor:"boolean,omitempty"`
+		Nested  *s     `cbor:"nested,omitempty"`
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		v := s{
+			String:  "hello world",
+			Integer: 42,
+			Boolean: true,
+			Nested: &s{
+				String:  "foo bar",
+				Integer: 1234567890,
+			},
+		}
+
+		raw, err := hex.DecodeString("a4666e6573746564a266737472696e6767666f6f2062617267696e74656765721a499602d266737472696e676b68656c6c6f20776f726c6467626f6f6c65616ef567696e7465676572182a")
+		require.NoError(t, err)
+
+		testCodec(t, new(CBORCodec), v, raw)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		v := map[string]any{
+			"string": "hello world",
+			"nested": map[string]any{
+				"boolean": true,
+				"array":   []any{"a", "b"},
+			},
+		}
+
+		raw, err := hex.DecodeString("a2666e6573746564a2656172726179826161616267626f6f6c65616ef566737472696e676b68656c6c6f20776f726c64")
+		require.NoError(t, err)
+
+		testCodec(t, new(CBORCodec), v, raw)
+	})
+}
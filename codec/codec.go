@@ -1,6 +1,11 @@
 package codec
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
 
 var registry map[string]Codec
 
@@ -13,12 +18,28 @@ func init() {
 	yaml := YAMLCodec{}
 	Register(".yaml", &yaml)
 	Register(".yml", &yaml)
+
+	cbor := CBORCodec{}
+	Register(".cbor", &cbor)
+
+	toml := TOMLCodec{}
+	Register(".toml", &toml)
+
+	csv := CSVCodec{}
+	Register(".csv", &csv)
+
+	RegisterLayer(".b64", &Base64Layer{})
 }
 
 func Register(ext string, codec Codec) {
 	registry[ext] = codec
 }
 
+// Unregister removes the codec registered for ext, if any.
+func Unregister(ext string) {
+	delete(registry, ext)
+}
+
 func Get(ext string) (Codec, error) {
 	if codec, ok := registry[ext]; ok {
 		return codec, nil
@@ -27,8 +48,107 @@ func Get(ext string) (Codec, error) {
 	return nil, fmt.Errorf("extension %q has no registered codec", ext)
 }
 
+// GetByName returns a registered codec by name (eg: "json"), which is the
+// codec registered for that extension, or otherwise the codec whose Name
+// matches (ignoring case) for the first extension in sorted order.
+func GetByName(name string) (Codec, error) {
+	if codec, ok := registry["."+name]; ok {
+		return codec, nil
+	}
+
+	exts := make([]string, 0, len(registry))
+	for ext := range registry {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+
+	for _, ext := range exts {
+		if strings.EqualFold(registry[ext].Name(), name) {
+			return registry[ext], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no codec named %q has been registered", name)
+}
+
+// SetDefaultIndent configures the indentation for the codec registered with
+// ext, allowing consistent formatting across all golden files without needing
+// to register new codecs. The codec must implement Indenter.
+//
+// The codec itself is left untouched, instead a configured copy replaces it
+// for ext (and any other extensions sharing it, such as ".yml" and ".yaml"),
+// so the change can be reverted using Snapshot.
+func SetDefaultIndent(ext string, indent int) error {
+	codec, err := Get(ext)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := codec.(Indenter); !ok {
+		return fmt.Errorf("codec %s does not support indentation", codec.Name())
+	}
+
+	c := copyCodec(codec)
+	c.(Indenter).SetIndent(indent)
+
+	for e, r := range registry {
+		if e == ext || samePointer(r, codec) {
+			registry[e] = c
+		}
+	}
+
+	return nil
+}
+
+// copyCodec returns a shallow copy of c when it is a pointer, so it can be
+// configured without modifying the original.
+func copyCodec(c Codec) Codec {
+	v := reflect.ValueOf(c)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return c
+	}
+
+	p := reflect.New(v.Elem().Type())
+	p.Elem().Set(v.Elem())
+	return p.Interface().(Codec)
+}
+
+// samePointer reports whether a and b are the same pointer.
+func samePointer(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Kind() == reflect.Ptr && va.Type() == vb.Type() && va.Pointer() == vb.Pointer()
+}
+
+// Indenter is an optional interface for a Codec with configurable indentation.
+type Indenter interface {
+	SetIndent(indent int)
+}
+
+// Stricter is an optional interface for a Codec which can reject fields that
+// are not defined by the struct being decoded into.
+type Stricter interface {
+	SetStrict(strict bool)
+}
+
 type Codec interface {
 	Name() string
 	Marshal(any) ([]byte, error)
 	Unmarshal([]byte, any) error
 }
+
+// Describer is an optional interface for a Codec to provide more details than
+// Name, such as the options it has been configured with (eg: "JSON indent=2"),
+// which is included in logs for reproducibility.
+type Describer interface {
+	Describe() string
+}
+
+// Describe returns the description for c if it implements Describer, otherwise
+// falling back to the Name.
+func Describe(c Codec) string {
+	if d, ok := c.(Describer); ok {
+		return d.Describe()
+	}
+
+	return c.Name()
+}
@@ -11,6 +11,7 @@ func TestGet(t *testing.T) {
 		c, err := Get(".json")
 		require.NoError(t, err)
 		require.IsType(t, new(JSONCodec), c)
+		require.False(t, c.(*JSONCodec).UseFloat64)
 	})
 
 	t.Run("yaml", func(t *testing.T) {
@@ -21,6 +22,24 @@ func TestGet(t *testing.T) {
 		}
 	})
 
+	t.Run("cbor", func(t *testing.T) {
+		c, err := Get(".cbor")
+		require.NoError(t, err)
+		require.IsType(t, new(CBORCodec), c)
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		c, err := Get(".toml")
+		require.NoError(t, err)
+		require.IsType(t, new(TOMLCodec), c)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		c, err := Get(".csv")
+		require.NoError(t, err)
+		require.IsType(t, new(CSVCodec), c)
+	})
+
 	t.Run("unknown", func(t *testing.T) {
 		c, err := Get(".unknown")
 		require.Error(t, err)
@@ -28,6 +47,30 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestGetByName(t *testing.T) {
+	t.Run("extension", func(t *testing.T) {
+		c, err := GetByName("yml")
+		require.NoError(t, err)
+		require.IsType(t, new(YAMLCodec), c)
+	})
+
+	t.Run("name", func(t *testing.T) {
+		defer Snapshot()()
+		Register(".j", &JSONCodec{Indent: "\t"})
+		Unregister(".json")
+
+		c, err := GetByName("JSON")
+		require.NoError(t, err)
+		require.Equal(t, "\t", c.(*JSONCodec).Indent)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		c, err := GetByName("nope")
+		require.EqualError(t, err, `no codec named "nope" has been registered`)
+		require.Nil(t, c)
+	})
+}
+
 func testCodec[T any](t *testing.T, c Codec, v1 T, expected []byte) {
 	t.Helper()
 
@@ -39,3 +82,83 @@ func testCodec[T any](t *testing.T, c Codec, v1 T, expected []byte) {
 	require.NoError(t, c.Unmarshal(actual, &v2))
 	require.EqualValues(t, v1, v2)
 }
+
+func TestDescribe(t *testing.T) {
+	t.Run("name", func(t *testing.T) {
+		require.Equal(t, "JSON", Describe(new(JSONCodec)))
+	})
+
+	t.Run("describer", func(t *testing.T) {
+		require.Equal(t, "test v1", Describe(new(describedCodec)))
+	})
+}
+
+type describedCodec struct {
+	JSONCodec
+}
+
+func (c *describedCodec) Describe() string {
+	return "test v1"
+}
+
+func TestSetDefaultIndent(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		t.Cleanup(Snapshot())
+
+		prev, err := Get(".json")
+		require.NoError(t, err)
+		indent := prev.(*JSONCodec).Indent
+
+		require.NoError(t, SetDefaultIndent(".json", 4))
+
+		c, err := Get(".json")
+		require.NoError(t, err)
+
+		actual, err := c.Marshal(map[string]string{"hello": "world"})
+		require.NoError(t, err)
+		require.Equal(t, "{\n    \"hello\": \"world\"\n}", string(actual))
+
+		// the previously registered codec is left untouched
+		require.Equal(t, indent, prev.(*JSONCodec).Indent)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		t.Cleanup(Snapshot())
+
+		prev, err := Get(".yml")
+		require.NoError(t, err)
+
+		require.NoError(t, SetDefaultIndent(".yml", 2))
+
+		for _, ext := range []string{".yml", ".yaml"} {
+			c, err := Get(ext)
+			require.NoError(t, err)
+			require.False(t, c == prev)
+
+			actual, err := c.Marshal(map[string]any{"a": map[string]string{"b": "c"}})
+			require.NoError(t, err)
+			require.Equal(t, "a:\n  b: c\n", string(actual), ext)
+		}
+
+		require.Equal(t, 0, prev.(*YAMLCodec).Indent)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		require.EqualError(t, SetDefaultIndent(".unknown", 2), `extension ".unknown" has no registered codec`)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		Register(".noindent", new(noIndentCodec))
+		t.Cleanup(func() { delete(registry, ".noindent") })
+
+		require.EqualError(t, SetDefaultIndent(".noindent", 2), "codec JSON does not support indentation")
+	})
+}
+
+type noIndentCodec struct {
+	Codec
+}
+
+func (c *noIndentCodec) Name() string {
+	return "JSON"
+}
@@ -0,0 +1,62 @@
+package codec
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+var contentTypes = map[string]string{
+	"application/json":   ".json",
+	"application/yaml":   ".yaml",
+	"application/x-yaml": ".yaml",
+	"text/yaml":          ".yaml",
+	"application/cbor":   ".cbor",
+	"application/toml":   ".toml",
+	"text/csv":           ".csv",
+}
+
+// RegisterContentType maps a media type (eg: "application/xml") to the
+// extension of a registered codec, for use by GetByContentType.
+func RegisterContentType(contentType, ext string) {
+	contentTypes[contentType] = ext
+}
+
+// GetByContentType returns the codec for a Content-Type header value (eg:
+// "application/json; charset=utf-8"). Structured syntax suffixes are also
+// supported, so "application/problem+json" uses the JSON codec.
+func GetByContentType(contentType string) (Codec, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+
+	ext, ok := mediaTypeExt(mediaType)
+	if !ok {
+		return nil, fmt.Errorf("content type %q has no registered codec", mediaType)
+	}
+
+	return Get(ext)
+}
+
+// ExtByContentType returns the extension of the codec used for a Content-Type
+// header value (see GetByContentType), or false when there is none.
+func ExtByContentType(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+
+	return mediaTypeExt(mediaType)
+}
+
+func mediaTypeExt(mediaType string) (string, bool) {
+	ext, ok := contentTypes[mediaType]
+	if !ok {
+		if i := strings.LastIndex(mediaType, "+"); i >= 0 {
+			ext, ok = contentTypes["application/"+mediaType[i+1:]]
+		}
+	}
+
+	return ext, ok
+}
@@ -0,0 +1,51 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetByContentType(t *testing.T) {
+	spec := []struct {
+		contentType string
+		expected    Codec
+	}{
+		{contentType: "application/json", expected: new(JSONCodec)},
+		{contentType: "application/json; charset=utf-8", expected: new(JSONCodec)},
+		{contentType: "application/problem+json", expected: new(JSONCodec)},
+		{contentType: "application/yaml", expected: new(YAMLCodec)},
+		{contentType: "text/yaml", expected: new(YAMLCodec)},
+		{contentType: "application/cbor", expected: new(CBORCodec)},
+	}
+
+	for _, s := range spec {
+		t.Run(s.contentType, func(t *testing.T) {
+			c, err := GetByContentType(s.contentType)
+			require.NoError(t, err)
+			require.IsType(t, s.expected, c)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := GetByContentType("text/html")
+		require.EqualError(t, err, `content type "text/html" has no registered codec`)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := GetByContentType("")
+		require.Error(t, err)
+	})
+}
+
+func TestExtByContentType(t *testing.T) {
+	ext, ok := ExtByContentType("application/problem+json; charset=utf-8")
+	require.True(t, ok)
+	require.Equal(t, ".json", ext)
+
+	_, ok = ExtByContentType("text/html")
+	require.False(t, ok)
+
+	_, ok = ExtByContentType("")
+	require.False(t, ok)
+}
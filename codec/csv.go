@@ -0,0 +1,245 @@
+package codec
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// CSVCodec decodes a CSV file with a header row into a slice of structs (or a
+// pointer to one), where each column is mapped to the field with a matching
+// `csv:"column"` struct tag (or the field name when there is no tag, while a
+// tag of "-" omits the field). Every field must have a column and every column
+// must have a field, so typos in either are reported rather than ignored.
+//
+// Fields can be strings, bools, numbers or types which implement
+// encoding.TextMarshaler and encoding.TextUnmarshaler.
+type CSVCodec struct{}
+
+func (c *CSVCodec) Name() string {
+	return "CSV"
+}
+
+func (c *CSVCodec) Marshal(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("csv requires a slice of structs, but got %T", v)
+	}
+
+	elem, ok := csvElem(rv.Type().Elem())
+	if !ok {
+		return nil, fmt.Errorf("csv requires a slice of structs, but got %T", v)
+	}
+
+	columns := csvColumns(elem)
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		row := rv.Index(i)
+		if row.Kind() == reflect.Ptr {
+			if row.IsNil() {
+				return nil, fmt.Errorf("csv row %d is nil", i)
+			}
+			row = row.Elem()
+		}
+
+		record := make([]string, len(columns))
+		for j, col := range columns {
+			s, err := formatCSV(row.Field(col.index))
+			if err != nil {
+				return nil, fmt.Errorf("csv row %d column %q: %w", i, col.name, err)
+			}
+			record[j] = s
+		}
+
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func (c *CSVCodec) Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("csv requires a pointer to a slice of structs, but got %T", v)
+	}
+
+	slice := rv.Elem()
+	elem, ok := csvElem(slice.Type().Elem())
+	if !ok {
+		return fmt.Errorf("csv requires a pointer to a slice of structs, but got %T", v)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	// empty input (or only a header) is an empty slice
+	result := reflect.MakeSlice(slice.Type(), 0, len(records))
+	if len(records) == 0 {
+		slice.Set(result)
+		return nil
+	}
+
+	columns := csvColumns(elem)
+	byName := make(map[string]csvColumn, len(columns))
+	for _, col := range columns {
+		byName[col.name] = col
+	}
+
+	header := records[0]
+	fields := make([]int, len(header))
+	found := make(map[string]bool, len(header))
+	for i, name := range header {
+		col, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("csv column %q has no matching field in %s", name, elem)
+		}
+		fields[i] = col.index
+		found[name] = true
+	}
+
+	for _, col := range columns {
+		if !found[col.name] {
+			return fmt.Errorf("csv column %q for field %s.%s is missing", col.name, elem, elem.Field(col.index).Name)
+		}
+	}
+
+	for n, record := range records[1:] {
+		row := reflect.New(elem).Elem()
+		for i, s := range record {
+			if err := parseCSV(s, row.Field(fields[i])); err != nil {
+				return fmt.Errorf("csv row %d column %q: %w", n+1, header[i], err)
+			}
+		}
+
+		if slice.Type().Elem().Kind() == reflect.Ptr {
+			result = reflect.Append(result, row.Addr())
+		} else {
+			result = reflect.Append(result, row)
+		}
+	}
+
+	slice.Set(result)
+	return nil
+}
+
+// csvColumn is a struct field mapped to a CSV column.
+type csvColumn struct {
+	name  string
+	index int
+}
+
+// csvElem returns the struct type for the elements of a slice, which may also
+// be pointers to structs.
+func csvElem(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ, typ.Kind() == reflect.Struct
+}
+
+// csvColumns returns the columns for the exported fields of typ in order.
+func csvColumns(typ reflect.Type) []csvColumn {
+	var columns []csvColumn
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, ok := field.Tag.Lookup("csv")
+		if name == "-" {
+			continue
+		} else if !ok || name == "" {
+			name = field.Name
+		}
+
+		columns = append(columns, csvColumn{name: name, index: i})
+	}
+	return columns
+}
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func formatCSV(v reflect.Value) (string, error) {
+	if v.Type().Implements(textMarshalerType) {
+		data, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(data), err
+	}
+
+	switch {
+	case v.Kind() == reflect.String:
+		return v.String(), nil
+	case v.Kind() == reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10), nil
+	case v.CanUint():
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case v.CanFloat():
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func parseCSV(s string, v reflect.Value) error {
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	var err error
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		v.SetBool(b)
+	case v.CanInt():
+		var i int64
+		i, err = strconv.ParseInt(s, 10, v.Type().Bits())
+		v.SetInt(i)
+	case v.CanUint():
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, v.Type().Bits())
+		v.SetUint(u)
+	case v.CanFloat():
+		var f float64
+		f, err = strconv.ParseFloat(s, v.Type().Bits())
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return err
+}
@@ -0,0 +1,85 @@
+package codec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVCodec(t *testing.T) {
+	type row struct {
+		Name    string    `csv:"name"`
+		Age     int       `csv:"age"`
+		Score   float64   `csv:"score"`
+		Active  bool      `csv:"active"`
+		Joined  time.Time `csv:"joined"`
+		Ignored string    `csv:"-"`
+	}
+
+	joined := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("round trip", func(t *testing.T) {
+		testCodec(t, new(CSVCodec), []row{
+			{Name: "alice", Age: 30, Score: 9.5, Active: true, Joined: joined},
+			{Name: "bob, jr", Age: 25, Score: 7, Joined: joined},
+		}, []byte(`name,age,score,active,joined
+alice,30,9.5,true,2024-01-02T03:04:05Z
+"bob, jr",25,7,false,2024-01-02T03:04:05Z
+`))
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		testCodec(t, new(CSVCodec), []*row{
+			{Name: "alice", Joined: joined},
+		}, []byte(`name,age,score,active,joined
+alice,0,0,false,2024-01-02T03:04:05Z
+`))
+	})
+
+	t.Run("column order", func(t *testing.T) {
+		var rows []row
+		require.NoError(t, new(CSVCodec).Unmarshal([]byte("joined,active,score,age,name\n2024-01-02T03:04:05Z,true,1,2,carol\n"), &rows))
+		require.Equal(t, []row{{Name: "carol", Age: 2, Score: 1, Active: true, Joined: joined}}, rows)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var rows []row
+		require.NoError(t, new(CSVCodec).Unmarshal(nil, &rows))
+		require.NotNil(t, rows)
+		require.Empty(t, rows)
+	})
+
+	t.Run("header only", func(t *testing.T) {
+		var rows []row
+		require.NoError(t, new(CSVCodec).Unmarshal([]byte("name,age,score,active,joined\n"), &rows))
+		require.NotNil(t, rows)
+		require.Empty(t, rows)
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		var rows []row
+		err := new(CSVCodec).Unmarshal([]byte("name,age,score,active\nalice,30,9.5,true\n"), &rows)
+		require.EqualError(t, err, `csv column "joined" for field codec.row.Joined is missing`)
+	})
+
+	t.Run("extra column", func(t *testing.T) {
+		var rows []row
+		err := new(CSVCodec).Unmarshal([]byte("name,age,score,active,joined,email\nalice,30,9.5,true,2024-01-02T03:04:05Z,a@example.com\n"), &rows)
+		require.EqualError(t, err, `csv column "email" has no matching field in codec.row`)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		var rows []row
+		err := new(CSVCodec).Unmarshal([]byte("name,age,score,active,joined\nalice,old,9.5,true,2024-01-02T03:04:05Z\n"), &rows)
+		require.EqualError(t, err, `csv row 1 column "age": strconv.ParseInt: parsing "old": invalid syntax`)
+	})
+
+	t.Run("not a slice", func(t *testing.T) {
+		var r row
+		require.EqualError(t, new(CSVCodec).Unmarshal([]byte("name\n"), &r), "csv requires a pointer to a slice of structs, but got *codec.row")
+
+		_, err := new(CSVCodec).Marshal(r)
+		require.EqualError(t, err, "csv requires a slice of structs, but got codec.row")
+	})
+}
@@ -0,0 +1,35 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Equivalent decodes dataA with a and dataB with b into fresh values of the
+// same type as proto (which may also be a pointer to that type), reporting
+// whether the results are deeply equal. This is useful for verifying that a
+// fixture migrated from one format to another (eg: JSON to YAML) still
+// represents the same value.
+func Equivalent(a, b Codec, dataA, dataB []byte, proto any) (bool, error) {
+	if proto == nil {
+		return false, errors.New("proto cannot be nil")
+	}
+
+	typ := reflect.TypeOf(proto)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	valueA := reflect.New(typ)
+	if err := a.Unmarshal(dataA, valueA.Interface()); err != nil {
+		return false, fmt.Errorf("%s decode error: %w", a.Name(), err)
+	}
+
+	valueB := reflect.New(typ)
+	if err := b.Unmarshal(dataB, valueB.Interface()); err != nil {
+		return false, fmt.Errorf("%s decode error: %w", b.Name(), err)
+	}
+
+	return reflect.DeepEqual(valueA.Interface(), valueB.Interface()), nil
+}
@@ -0,0 +1,48 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEquivalent(t *testing.T) {
+	type s struct {
+		String  string   `json:"string" yaml:"string"`
+		Integer int      `json:"integer" yaml:"integer"`
+		Array   []string `json:"array" yaml:"array"`
+	}
+
+	data := []byte(`{"string": "hello world", "integer": 42, "array": ["a", "b"]}`)
+
+	t.Run("equivalent", func(t *testing.T) {
+		yaml := []byte("string: hello world\ninteger: 42\narray:\n  - a\n  - b\n")
+
+		for _, proto := range []any{s{}, new(s)} {
+			ok, err := Equivalent(new(JSONCodec), new(YAMLCodec), data, yaml, proto)
+			require.NoError(t, err)
+			require.True(t, ok)
+		}
+	})
+
+	t.Run("different", func(t *testing.T) {
+		yaml := []byte("string: hello world\ninteger: 43\narray:\n  - a\n  - b\n")
+
+		ok, err := Equivalent(new(JSONCodec), new(YAMLCodec), data, yaml, s{})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		ok, err := Equivalent(new(JSONCodec), new(YAMLCodec), data, []byte("integer: [a"), s{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "YAML decode error:")
+		require.False(t, ok)
+	})
+
+	t.Run("nil proto", func(t *testing.T) {
+		ok, err := Equivalent(new(JSONCodec), new(YAMLCodec), data, data, nil)
+		require.EqualError(t, err, "proto cannot be nil")
+		require.False(t, ok)
+	})
+}
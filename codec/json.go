@@ -3,17 +3,58 @@ package codec
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 )
 
 type JSONCodec struct {
 	Indent string
+
+	// TrailingNewline appends a newline to the marshaled output, matching what
+	// most editors and tools (eg: jq, prettier) produce. Decoding tolerates the
+	// newline regardless of this setting.
+	TrailingNewline bool
+
+	// UseFloat64 decodes numbers into any values as float64 (as encoding/json
+	// does by default) rather than json.Number, which loses precision for large
+	// integers. Assert compares json.Number values loosely against other
+	// numeric types, so either setting can be compared against values built in
+	// code.
+	UseFloat64 bool
+
+	// DisallowUnknownFields makes decoding into a struct fail when the input
+	// contains a field which the struct does not define.
+	DisallowUnknownFields bool
 }
 
 func (c *JSONCodec) Name() string {
 	return "JSON"
 }
 
+// SetIndent configures the indent to be the given number of spaces, with 0
+// meaning no indentation at all.
+func (c *JSONCodec) SetIndent(indent int) {
+	c.Indent = strings.Repeat(" ", indent)
+}
+
+// SetStrict configures whether unknown fields are rejected when decoding.
+func (c *JSONCodec) SetStrict(strict bool) {
+	c.DisallowUnknownFields = strict
+}
+
 func (c *JSONCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.TrailingNewline {
+		data = append(data, '\n')
+	}
+
+	return data, nil
+}
+
+func (c *JSONCodec) marshal(v any) ([]byte, error) {
 	if c.Indent != "" {
 		return json.MarshalIndent(v, "", c.Indent)
 	} else {
@@ -24,6 +65,11 @@ func (c *JSONCodec) Marshal(v any) ([]byte, error) {
 func (c *JSONCodec) Unmarshal(data []byte, v any) error {
 	r := bytes.NewBuffer(data)
 	d := json.NewDecoder(r)
-	d.UseNumber()
+	if !c.UseFloat64 {
+		d.UseNumber()
+	}
+	if c.DisallowUnknownFields {
+		d.DisallowUnknownFields()
+	}
 	return d.Decode(v)
 }
@@ -45,6 +45,58 @@ func TestJSONCodec(t *testing.T) {
 		testCodec(t, &JSONCodec{Indent: "    "}, v, json.RawMessage(raw))
 	})
 
+	t.Run("trailing newline", func(t *testing.T) {
+		raw := `{"string":"hello world","integer":42,"boolean":true,"nested":{"string":"foo bar","integer":1234567890}}` + "\n"
+		testCodec(t, &JSONCodec{TrailingNewline: true}, v, json.RawMessage(raw))
+	})
+
+	t.Run("trailing newline indent", func(t *testing.T) {
+		raw := `{
+  "string": "hello world",
+  "integer": 42,
+  "boolean": true,
+  "nested": {
+    "string": "foo bar",
+    "integer": 1234567890
+  }
+}
+`
+		testCodec(t, &JSONCodec{Indent: "  ", TrailingNewline: true}, v, json.RawMessage(raw))
+	})
+
+	t.Run("trailing newline tolerated", func(t *testing.T) {
+		for _, c := range []*JSONCodec{{}, {TrailingNewline: true}} {
+			var actual s
+			require.NoError(t, c.Unmarshal([]byte(`{"string":"hello world"}`+"\n"), &actual))
+			require.Equal(t, s{String: "hello world"}, actual)
+
+			require.NoError(t, c.Unmarshal([]byte(`{"string":"hello world"}`), &actual))
+			require.Equal(t, s{String: "hello world"}, actual)
+		}
+	})
+
+	t.Run("number", func(t *testing.T) {
+		var actual any
+		require.NoError(t, new(JSONCodec).Unmarshal([]byte(`42`), &actual))
+		require.Equal(t, json.Number("42"), actual)
+	})
+
+	t.Run("use float64", func(t *testing.T) {
+		var actual any
+		require.NoError(t, (&JSONCodec{UseFloat64: true}).Unmarshal([]byte(`42`), &actual))
+		require.Equal(t, float64(42), actual)
+	})
+
+	t.Run("disallow unknown fields", func(t *testing.T) {
+		var actual s
+		require.NoError(t, new(JSONCodec).Unmarshal([]byte(`{"string":"hello world","unknown":true}`), &actual))
+		require.Equal(t, s{String: "hello world"}, actual)
+
+		c := new(JSONCodec)
+		c.SetStrict(true)
+		require.EqualError(t, c.Unmarshal([]byte(`{"string":"hello world","unknown":true}`), &actual), `json: unknown field "unknown"`)
+	})
+
 	t.Run("max int", func(t *testing.T) {
 		c := new(JSONCodec)
 
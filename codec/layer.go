@@ -0,0 +1,107 @@
+package codec
+
+import "fmt"
+
+var layers = make(map[string]Layer)
+
+// Layer is a transformation applied to raw bytes (eg: encoding or compression)
+// rather than a full codec. Files with a layer extension (eg: "data.json.b64")
+// are decoded by the layer first and then passed to the codec for the next
+// extension, which allows binary formats to be stored as diffable text.
+type Layer interface {
+	Name() string
+	Encode([]byte) ([]byte, error)
+	Decode([]byte) ([]byte, error)
+}
+
+func RegisterLayer(ext string, layer Layer) {
+	layers[ext] = layer
+}
+
+func GetLayer(ext string) (Layer, error) {
+	if layer, ok := layers[ext]; ok {
+		return layer, nil
+	}
+
+	return nil, fmt.Errorf("extension %q has no registered layer", ext)
+}
+
+// Chain returns a Codec which marshals using inner and then encodes the result
+// with outer, unmarshaling in the reverse order. The returned Codec implements
+// Indenter and Stricter when inner does, which configure a copy of inner.
+func Chain(outer Layer, inner Codec) Codec {
+	c := chainCodec{outer: outer, inner: inner}
+
+	_, indenter := inner.(Indenter)
+	_, stricter := inner.(Stricter)
+
+	switch {
+	case indenter && stricter:
+		return &indentStrictChainCodec{c}
+	case indenter:
+		return &indentChainCodec{c}
+	case stricter:
+		return &strictChainCodec{c}
+	default:
+		return &c
+	}
+}
+
+type chainCodec struct {
+	outer Layer
+	inner Codec
+}
+
+func (c *chainCodec) Name() string {
+	return c.inner.Name() + "+" + c.outer.Name()
+}
+
+func (c *chainCodec) Describe() string {
+	return Describe(c.inner) + "+" + c.outer.Name()
+}
+
+func (c *chainCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.outer.Encode(data)
+}
+
+func (c *chainCodec) Unmarshal(data []byte, v any) error {
+	data, err := c.outer.Decode(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.outer.Name(), err)
+	}
+
+	return c.inner.Unmarshal(data, v)
+}
+
+// setIndent replaces inner with a copy using indent, since inner is usually
+// the registered codec.
+func (c *chainCodec) setIndent(indent int) {
+	c.inner = copyCodec(c.inner)
+	c.inner.(Indenter).SetIndent(indent)
+}
+
+// setStrict replaces inner with a copy using strict, since inner is usually
+// the registered codec.
+func (c *chainCodec) setStrict(strict bool) {
+	c.inner = copyCodec(c.inner)
+	c.inner.(Stricter).SetStrict(strict)
+}
+
+type indentChainCodec struct{ chainCodec }
+
+func (c *indentChainCodec) SetIndent(indent int) { c.setIndent(indent) }
+
+type strictChainCodec struct{ chainCodec }
+
+func (c *strictChainCodec) SetStrict(strict bool) { c.setStrict(strict) }
+
+type indentStrictChainCodec struct{ chainCodec }
+
+func (c *indentStrictChainCodec) SetIndent(indent int) { c.setIndent(indent) }
+
+func (c *indentStrictChainCodec) SetStrict(strict bool) { c.setStrict(strict) }
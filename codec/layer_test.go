@@ -0,0 +1,86 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLayer(t *testing.T) {
+	t.Run("base64", func(t *testing.T) {
+		l, err := GetLayer(".b64")
+		require.NoError(t, err)
+		require.IsType(t, new(Base64Layer), l)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		l, err := GetLayer(".unknown")
+		require.Error(t, err)
+		require.Nil(t, l)
+	})
+}
+
+func TestChain(t *testing.T) {
+	c := Chain(new(Base64Layer), new(JSONCodec))
+
+	require.Equal(t, "JSON+base64", c.Name())
+	require.Equal(t, "JSON+base64", Describe(c))
+
+	testCodec(t, c, map[string]string{"a": "hello"}, []byte("eyJhIjoiaGVsbG8ifQ=="))
+
+	t.Run("decode error", func(t *testing.T) {
+		var v map[string]string
+		require.EqualError(t, c.Unmarshal([]byte("not base64!"), &v), "base64: illegal base64 data at input byte 3")
+	})
+
+	t.Run("indent", func(t *testing.T) {
+		inner := new(JSONCodec)
+		c := Chain(new(Base64Layer), inner)
+		c.(Indenter).SetIndent(2)
+
+		data, err := c.Marshal(map[string]string{"a": "hello"})
+		require.NoError(t, err)
+		require.Equal(t, "ewogICJhIjogImhlbGxvIgp9", string(data))
+		require.Equal(t, "", inner.Indent, "inner codec should not be modified")
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		inner := new(JSONCodec)
+		c := Chain(new(Base64Layer), inner)
+		c.(Stricter).SetStrict(true)
+
+		var v struct{ A string }
+		require.EqualError(t, c.Unmarshal([]byte("eyJiIjoiaGVsbG8ifQ=="), &v), `json: unknown field "b"`)
+		require.NoError(t, inner.Unmarshal([]byte(`{"b":"hello"}`), &v), "inner codec should not be modified")
+	})
+
+	t.Run("without indent or strict", func(t *testing.T) {
+		c := Chain(new(Base64Layer), new(CBORCodec))
+
+		_, ok := c.(Indenter)
+		require.False(t, ok)
+
+		_, ok = c.(Stricter)
+		require.False(t, ok)
+	})
+}
+
+func TestBase64Layer(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		l := new(Base64Layer)
+
+		data, err := l.Encode([]byte("hello world"))
+		require.NoError(t, err)
+		require.Equal(t, "aGVsbG8gd29ybGQ=", string(data))
+
+		data, err = l.Decode([]byte("aGVsbG8gd29ybGQ=\n"))
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(data))
+	})
+
+	t.Run("trailing newline", func(t *testing.T) {
+		data, err := (&Base64Layer{TrailingNewline: true}).Encode([]byte("hello world"))
+		require.NoError(t, err)
+		require.Equal(t, "aGVsbG8gd29ybGQ=\n", string(data))
+	})
+}
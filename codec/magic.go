@@ -0,0 +1,41 @@
+package codec
+
+import "bytes"
+
+type magic struct {
+	prefix []byte
+	codec  Codec
+}
+
+var magics []magic
+
+// RegisterMagic registers c for data which starts with prefix (eg: the
+// "\x89PNG" header of an image), for files whose extension has no registered
+// codec. When multiple prefixes match, the longest one is used.
+func RegisterMagic(prefix []byte, c Codec) {
+	for i, m := range magics {
+		if bytes.Equal(m.prefix, prefix) {
+			magics[i].codec = c
+			return
+		}
+	}
+
+	magics = append(magics, magic{prefix: append([]byte(nil), prefix...), codec: c})
+}
+
+// GetByMagic returns the codec registered with RegisterMagic for the longest
+// prefix of data, or false when there is none.
+func GetByMagic(data []byte) (Codec, bool) {
+	var match *magic
+	for i, m := range magics {
+		if bytes.HasPrefix(data, m.prefix) && (match == nil || len(m.prefix) > len(match.prefix)) {
+			match = &magics[i]
+		}
+	}
+
+	if match == nil {
+		return nil, false
+	}
+
+	return match.codec, true
+}
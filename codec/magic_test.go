@@ -0,0 +1,47 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetByMagic(t *testing.T) {
+	t.Cleanup(Snapshot())
+
+	json := new(JSONCodec)
+	cbor := new(CBORCodec)
+
+	RegisterMagic([]byte{0x1f, 0x8b}, json)
+	RegisterMagic([]byte{0x1f, 0x8b, 0x08}, cbor)
+
+	t.Run("longest prefix", func(t *testing.T) {
+		c, ok := GetByMagic([]byte{0x1f, 0x8b, 0x08, 0x00})
+		require.True(t, ok)
+		require.IsType(t, cbor, c)
+	})
+
+	t.Run("shorter prefix", func(t *testing.T) {
+		c, ok := GetByMagic([]byte{0x1f, 0x8b, 0x09})
+		require.True(t, ok)
+		require.IsType(t, json, c)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		_, ok := GetByMagic([]byte("hello"))
+		require.False(t, ok)
+
+		_, ok = GetByMagic(nil)
+		require.False(t, ok)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		yaml := new(YAMLCodec)
+		RegisterMagic([]byte{0x1f, 0x8b}, yaml)
+
+		c, ok := GetByMagic([]byte{0x1f, 0x8b, 0x09})
+		require.True(t, ok)
+		require.IsType(t, yaml, c)
+		require.Len(t, magics, 2)
+	})
+}
@@ -0,0 +1,64 @@
+package codec
+
+import "reflect"
+
+// Snapshot captures the registered codecs, layers, content types and magic
+// signatures, returning a function which restores them. This allows a test to
+// change the registry freely without leaking into other tests in the same
+// binary (eg: `t.Cleanup(codec.Snapshot())`).
+//
+// Codecs (and layers) which are pointers are restored to their state at the
+// time of the snapshot too, which reverts any changes made to them in place.
+func Snapshot() func() {
+	savedRegistry := copyMap(registry)
+	savedLayers := copyMap(layers)
+	savedContentTypes := copyMap(contentTypes)
+	savedMagics := append([]magic(nil), magics...)
+
+	states := make(map[any]reflect.Value)
+	for _, c := range registry {
+		saveState(states, c)
+	}
+	for _, l := range layers {
+		saveState(states, l)
+	}
+	for _, m := range magics {
+		saveState(states, m.codec)
+	}
+
+	return func() {
+		registry = copyMap(savedRegistry)
+		layers = copyMap(savedLayers)
+		contentTypes = copyMap(savedContentTypes)
+		magics = append([]magic(nil), savedMagics...)
+
+		for v, state := range states {
+			reflect.ValueOf(v).Elem().Set(state)
+		}
+	}
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	c := make(map[K]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// saveState records a copy of the value pointed to by v, so it can be restored
+// after being modified in place.
+func saveState(states map[any]reflect.Value, v any) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return
+	}
+
+	if _, ok := states[v]; ok {
+		return
+	}
+
+	state := reflect.New(rv.Elem().Type()).Elem()
+	state.Set(rv.Elem())
+	states[v] = state
+}
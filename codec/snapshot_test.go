@@ -0,0 +1,80 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	json, err := Get(".json")
+	require.NoError(t, err)
+	yaml, err := Get(".yaml")
+	require.NoError(t, err)
+
+	restore := Snapshot()
+
+	Register(".custom", new(JSONCodec))
+	Unregister(".json")
+	RegisterLayer(".custom", &Base64Layer{})
+	RegisterContentType("application/custom", ".custom")
+	RegisterMagic([]byte("CUSTOM"), new(JSONCodec))
+	require.NoError(t, SetDefaultIndent(".yaml", 8))
+
+	_, err = Get(".json")
+	require.Error(t, err)
+	indented, err := Get(".yaml")
+	require.NoError(t, err)
+	require.Equal(t, 8, indented.(*YAMLCodec).Indent)
+
+	restore()
+
+	t.Run("registry", func(t *testing.T) {
+		c, err := Get(".json")
+		require.NoError(t, err)
+		require.True(t, c == json)
+
+		_, err = Get(".custom")
+		require.Error(t, err)
+	})
+
+	t.Run("layers", func(t *testing.T) {
+		_, err := GetLayer(".custom")
+		require.Error(t, err)
+
+		_, err = GetLayer(".b64")
+		require.NoError(t, err)
+	})
+
+	t.Run("content types", func(t *testing.T) {
+		_, ok := ExtByContentType("application/custom")
+		require.False(t, ok)
+	})
+
+	t.Run("magic", func(t *testing.T) {
+		_, ok := GetByMagic([]byte("CUSTOM data"))
+		require.False(t, ok)
+	})
+
+	t.Run("codec state", func(t *testing.T) {
+		c, err := Get(".yaml")
+		require.NoError(t, err)
+		require.True(t, c == yaml)
+		require.Equal(t, 0, c.(*YAMLCodec).Indent)
+	})
+}
+
+func TestUnregister(t *testing.T) {
+	t.Cleanup(Snapshot())
+
+	Register(".tmp", new(JSONCodec))
+	_, err := Get(".tmp")
+	require.NoError(t, err)
+
+	Unregister(".tmp")
+	_, err = Get(".tmp")
+	require.EqualError(t, err, `extension ".tmp" has no registered codec`)
+
+	// unregistering a missing extension is a no-op
+	Unregister(".tmp")
+}
@@ -0,0 +1,57 @@
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+type TOMLCodec struct {
+	// Indent is used for each level of nested tables and arrays, where the
+	// default of "" means no indentation at all.
+	Indent string
+
+	// DisallowUnknownFields makes decoding into a struct fail when the input
+	// contains a key which the struct does not define.
+	DisallowUnknownFields bool
+}
+
+func (c *TOMLCodec) Name() string {
+	return "TOML"
+}
+
+// SetIndent configures the indent to be the given number of spaces, with 0
+// meaning no indentation at all.
+func (c *TOMLCodec) SetIndent(indent int) {
+	c.Indent = strings.Repeat(" ", indent)
+}
+
+// SetStrict configures whether unknown keys are rejected when decoding.
+func (c *TOMLCodec) SetStrict(strict bool) {
+	c.DisallowUnknownFields = strict
+}
+
+func (c *TOMLCodec) Marshal(v any) ([]byte, error) {
+	var b bytes.Buffer
+	e := toml.NewEncoder(&b)
+	e.Indent = c.Indent
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (c *TOMLCodec) Unmarshal(data []byte, v any) error {
+	md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(v)
+	if err != nil {
+		return err
+	}
+
+	if keys := md.Undecoded(); c.DisallowUnknownFields && len(keys) > 0 {
+		return fmt.Errorf("unknown keys %s", keys)
+	}
+
+	return nil
+}
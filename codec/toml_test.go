@@ -0,0 +1,72 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOMLCodec(t *testing.T) {
+	type s struct {
+		String  string `toml:"string,omitempty"`
+		Integer int    `toml:"integer,omitempty"`
+		Boolean bool   `toml:"boolean,omitempty"`
+		Nested  *s     `toml:"nested,omitempty"`
+	}
+
+	v := s{
+		String:  "hello world",
+		Integer: 42,
+		Boolean: true,
+		Nested: &s{
+			String:  "foo bar",
+			Integer: 1234567890,
+		},
+	}
+
+	t.Run("indent default", func(t *testing.T) {
+		testCodec(t, new(TOMLCodec), v, []byte(`string = "hello world"
+integer = 42
+boolean = true
+
+[nested]
+string = "foo bar"
+integer = 1234567890
+`))
+	})
+
+	t.Run("indent custom", func(t *testing.T) {
+		c := new(TOMLCodec)
+		c.SetIndent(2)
+
+		testCodec(t, c, v, []byte(`string = "hello world"
+integer = 42
+boolean = true
+
+[nested]
+  string = "foo bar"
+  integer = 1234567890
+`))
+	})
+
+	t.Run("map", func(t *testing.T) {
+		testCodec(t, new(TOMLCodec), map[string]any{
+			"name": "alice",
+			"tags": []any{"admin", "dev"},
+		}, []byte(`name = "alice"
+tags = ["admin", "dev"]
+`))
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		data := []byte("string = \"a\"\nextra = true\n")
+
+		var lenient s
+		require.NoError(t, new(TOMLCodec).Unmarshal(data, &lenient))
+		require.Equal(t, "a", lenient.String)
+
+		c := new(TOMLCodec)
+		c.SetStrict(true)
+		require.EqualError(t, c.Unmarshal(data, new(s)), "unknown keys [extra]")
+	})
+}
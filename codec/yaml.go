@@ -9,12 +9,24 @@ import (
 
 type YAMLCodec struct {
 	Indent int
+
+	// PreserveComments decodes into any values as a *yaml.Node tree rather
+	// than plain maps and slices, so the comments (and key order) from the
+	// original document are kept when it is encoded again. Fields using
+	// yaml.Node directly always preserve comments, regardless of this setting.
+	PreserveComments bool
 }
 
 func (c *YAMLCodec) Name() string {
 	return "YAML"
 }
 
+// SetIndent configures the indent to be the given number of spaces, with 0
+// meaning the default indentation.
+func (c *YAMLCodec) SetIndent(indent int) {
+	c.Indent = indent
+}
+
 func (c *YAMLCodec) Marshal(v any) ([]byte, error) {
 	if c.Indent > 0 {
 		return yamlMarshalIndent(c.Indent, v)
@@ -24,6 +36,15 @@ func (c *YAMLCodec) Marshal(v any) ([]byte, error) {
 }
 
 func (c *YAMLCodec) Unmarshal(data []byte, v any) error {
+	if p, ok := v.(*any); ok && c.PreserveComments {
+		node := new(yaml.Node)
+		if err := yaml.Unmarshal(data, node); err != nil {
+			return err
+		}
+		*p = node
+		return nil
+	}
+
 	return yaml.Unmarshal(data, v)
 }
 
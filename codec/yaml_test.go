@@ -2,6 +2,7 @@ package codec
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -43,3 +44,49 @@ nested:
 `))
 	})
 }
+
+func TestYAMLCodecPreserveComments(t *testing.T) {
+	input := `# the name of the user
+name: alice # inline
+roles:
+  # always present
+  - admin
+  - editor
+`
+
+	t.Run("enabled", func(t *testing.T) {
+		c := &YAMLCodec{Indent: 2, PreserveComments: true}
+
+		var v any
+		if err := c.Unmarshal([]byte(input), &v); err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := c.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(output) != input {
+			t.Fatalf("comments were not preserved:\n%s", output)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		c := &YAMLCodec{Indent: 2}
+
+		var v any
+		if err := c.Unmarshal([]byte(input), &v); err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := c.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(string(output), "#") {
+			t.Fatalf("comments were unexpectedly preserved:\n%s", output)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package got
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strconv"
+)
+
+// RunCommand executes the command described by args (the name followed by any
+// arguments) and asserts the captured output against golden files in the test
+// case: "stdout.txt" and "stderr.txt" for the output streams, and "exit.txt"
+// for a non-zero exit code. As with Assert, the golden files are updated when
+// the "update-golden" flag is provided, and empty outputs have no file.
+func RunCommand(t tester, tc TestCase, args ...string) {
+	t.Helper()
+
+	if len(args) == 0 {
+		t.Fatal("[GoT] RunCommand: a command is required")
+		return
+	}
+
+	output, err := runCommand(args)
+	if err != nil {
+		t.Fatalf("[GoT] RunCommand: %s", err.Error())
+		return
+	}
+
+	tc.Assert(t, output)
+}
+
+// commandOutput is what RunCommand asserts against the golden files.
+type commandOutput struct {
+	Stdout   string `testdata:"stdout.txt"`
+	Stderr   string `testdata:"stderr.txt"`
+	ExitCode string `testdata:"exit.txt,trim"`
+}
+
+func runCommand(args []string) (*commandOutput, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	output := new(commandOutput)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
+		}
+
+		output.ExitCode = strconv.Itoa(exitErr.ExitCode())
+	}
+
+	output.Stdout = stdout.String()
+	output.Stderr = stderr.String()
+
+	return output, nil
+}
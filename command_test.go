@@ -0,0 +1,59 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommand(t *testing.T) {
+	t.Run("stdout", func(t *testing.T) {
+		var mt mockT
+		RunCommand(&mt, TestCase{Dir: "testdata/command/echo"}, "echo", "hello world")
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("stderr and exit code", func(t *testing.T) {
+		var mt mockT
+		RunCommand(&mt, TestCase{Dir: "testdata/command/fail"}, "sh", "-c", "echo oops >&2; exit 3")
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		var mt mockT
+		RunCommand(&mt, TestCase{Dir: "testdata/command/echo"}, "echo", "goodbye")
+
+		require.True(t, mt.failed)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		RunCommand(&mt, TestCase{Dir: dir}, "echo", "hello world")
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "stdout.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello world\n", string(data))
+
+		for _, file := range []string{"stderr.txt", "exit.txt"} {
+			_, err := os.Stat(filepath.Join(dir, file))
+			require.True(t, os.IsNotExist(err), file)
+		}
+	})
+
+	t.Run("command not found", func(t *testing.T) {
+		var mt mockT
+		RunCommand(&mt, TestCase{Dir: "testdata/command/echo"}, "got-command-does-not-exist")
+
+		require.True(t, mt.failed)
+	})
+}
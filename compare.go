@@ -0,0 +1,418 @@
+package got
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/fatih/structtag"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ignoreKeys returns a shallow copy of input (a pointer to a struct) where the
+// fields using the "ignorekeys" option have those keys removed, so they can be
+// compared or saved without any volatile data. The keys are removed using the
+// same codec that the field is loaded from (or saved to) dir with.
+//
+// The option value is a list of keys separated by "|", where each key can be
+// a dotted path to reach into nested objects (eg: "ignorekeys=id|meta.time").
+func (o Options) ignoreKeys(dir string, input any) (any, error) {
+	if input == nil || reflect.TypeOf(input).Kind() != reflect.Ptr || reflect.TypeOf(input).Elem().Kind() != reflect.Struct {
+		return input, nil // invalid inputs are reported elsewhere
+	}
+
+	o, err := o.withDirConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := reflect.TypeOf(input).Elem()
+	output := reflect.New(typ)
+	output.Elem().Set(reflect.ValueOf(input).Elem())
+
+	err = walkDirFields(dir, output.Interface(), func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		if tag.HasOption("explode") || value.IsZero() {
+			return nil
+		}
+
+		keys, ok := getTagOption(tag, "ignorekeys")
+		if !ok {
+			return nil
+		}
+
+		file := o.fieldPipeline(field).codecFile(firstAlternate(testdataFile(field, tag, value)))
+
+		c, err := o.getCodec(file, field)
+		if err == nil {
+			err = ignoreKeysValue(c, value, strings.Split(keys, "|"))
+		}
+		if err != nil {
+			return fmt.Errorf("%s.%s: failed to ignore keys: %w", getTypeName(input), field.Name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Interface(), nil
+}
+
+func ignoreKeysValue(c codec.Codec, value reflect.Value, keys []string) error {
+	var data []byte
+	var err error
+
+	switch {
+	case isBytes(value.Type()):
+		data = value.Bytes()
+	case isString(value.Type()):
+		data = []byte(value.String())
+	default:
+		data, err = c.Marshal(value.Interface())
+		if err != nil {
+			return err
+		}
+	}
+
+	var generic any
+	if err := c.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		deleteKey(generic, strings.Split(key, "."))
+	}
+
+	data, err = c.Marshal(generic)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case isBytes(value.Type()):
+		value.SetBytes(data)
+	case isString(value.Type()):
+		value.SetString(string(data))
+	default:
+		p := reflect.New(value.Type())
+		if err := c.Unmarshal(data, p.Interface()); err != nil {
+			return err
+		}
+		value.Set(p.Elem())
+	}
+
+	return nil
+}
+
+// deleteKey removes the key at path from v, descending into both objects and
+// each element of arrays along the way. Codecs which preserve comments decode
+// into a *yaml.Node tree instead, which is handled the same way.
+func deleteKey(v any, path []string) {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			delete(v, path[0])
+		} else if next, ok := v[path[0]]; ok {
+			deleteKey(next, path[1:])
+		}
+	case []any:
+		for _, item := range v {
+			deleteKey(item, path)
+		}
+	case *yaml.Node:
+		deleteNodeKey(v, path)
+	}
+}
+
+// deleteNodeKey removes the key at path from node, where the content of a
+// mapping node alternates between keys and values.
+func deleteNodeKey(node *yaml.Node, path []string) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, item := range node.Content {
+			deleteNodeKey(item, path)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != path[0] {
+				continue
+			}
+
+			if len(path) == 1 {
+				node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			} else {
+				deleteNodeKey(node.Content[i+1], path[1:])
+			}
+
+			return
+		}
+	}
+}
+
+// compareOptions returns the cmp options used when comparing values of the
+// same type as v. Since only exported fields are ever loaded or saved, the
+// unexported fields of v itself are ignored rather than causing cmp to panic,
+// as are those of nested structs within fields using the "ignoreunexported"
+// option (see unexportedStructs).
+//
+// Numbers are also compared by value regardless of their representation, since
+// JSONCodec decodes untyped numbers as json.Number while values built in code
+// typically use float64 or int. Fields using the "jsoneq" option compare their
+// json.RawMessage values ignoring insignificant whitespace (see jsonEqOptions).
+// Slice fields using the "unordered" option are compared ignoring order, fields
+// using the "comparecodec" option are compared once decoded, and fields
+// registered with IgnoreFieldsGlobally are not compared at all.
+func compareOptions(v any) []cmp.Option {
+	codecOpts, isCompareCodec := compareCodecOptions(v)
+
+	opts := []cmp.Option{
+		cmp.FilterValues(isLooseNumberPair, cmp.Comparer(equalNumbers)),
+	}
+
+	opts = append(opts, codecOpts...)
+	opts = append(opts, jsonEqOptions(v, isCompareCodec)...)
+	opts = append(opts, unorderedOptions(v)...)
+
+	if ignored := globallyIgnoredFields(); len(ignored) > 0 {
+		opts = append(opts, cmp.FilterPath(func(p cmp.Path) bool {
+			sf, ok := p.Last().(cmp.StructField)
+			return ok && ignored[sf.Name()]
+		}, cmp.Ignore()))
+	}
+
+	if structs := unexportedStructs(v); len(structs) > 0 {
+		opts = append(opts, cmpopts.IgnoreUnexported(structs...))
+	}
+
+	return opts
+}
+
+// ignoredFields are the struct field names registered by IgnoreFieldsGlobally.
+var (
+	ignoredFields   = make(map[string]bool)
+	ignoredFieldsMu sync.RWMutex
+)
+
+// IgnoreFieldsGlobally makes Assert ignore struct fields with any of the given
+// names when comparing, regardless of which type they belong to (eg: every
+// "CreatedAt" field, including those of nested structs). Since this applies to
+// the whole package, it is typically called once from TestMain or an init
+// func. The fields are still saved when updating golden files.
+//
+// The returned func stops ignoring the names which were not already ignored,
+// such as `t.Cleanup(got.IgnoreFieldsGlobally("CreatedAt"))`.
+func IgnoreFieldsGlobally(names ...string) func() {
+	ignoredFieldsMu.Lock()
+	defer ignoredFieldsMu.Unlock()
+
+	var added []string
+	for _, name := range names {
+		if !ignoredFields[name] {
+			ignoredFields[name] = true
+			added = append(added, name)
+		}
+	}
+
+	return func() {
+		ignoredFieldsMu.Lock()
+		defer ignoredFieldsMu.Unlock()
+
+		for _, name := range added {
+			delete(ignoredFields, name)
+		}
+	}
+}
+
+// globallyIgnoredFields returns a copy of the names registered with
+// IgnoreFieldsGlobally.
+func globallyIgnoredFields() map[string]bool {
+	ignoredFieldsMu.RLock()
+	defer ignoredFieldsMu.RUnlock()
+
+	if len(ignoredFields) == 0 {
+		return nil
+	}
+
+	names := make(map[string]bool, len(ignoredFields))
+	for name := range ignoredFields {
+		names[name] = true
+	}
+	return names
+}
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// isLooseNumberPair reports whether x and y are both numbers where at least
+// one is a json.Number, as values of the same type are compared normally.
+func isLooseNumberPair(x, y any) bool {
+	_, okX := x.(json.Number)
+	_, okY := y.(json.Number)
+	return (okX || okY) && toRat(x) != nil && toRat(y) != nil
+}
+
+func equalNumbers(x, y any) bool {
+	return toRat(x).Cmp(toRat(y)) == 0
+}
+
+const jsonEqOption = "jsoneq"
+
+// jsonEqOptions returns the cmp options for the fields of v (a pointer to a
+// struct) using the "jsoneq" option, which compare json.RawMessage values
+// anywhere within those fields ignoring insignificant whitespace, since they
+// are re-indented when saved (eg: `testdata:"input.json,jsoneq"` for a
+// map[string]json.RawMessage). Fields using "comparecodec" are left alone, as
+// they are already compared once decoded.
+func jsonEqOptions(v any, isCompareCodec func(cmp.Path) bool) []cmp.Option {
+	if v == nil || reflect.TypeOf(v).Kind() != reflect.Ptr || reflect.TypeOf(v).Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	typ := reflect.TypeOf(v).Elem()
+
+	var opts []cmp.Option
+	_ = walkFields(reflect.New(typ).Interface(), func(field reflect.StructField, _ reflect.Value, tag *structtag.Tag) error {
+		if !tag.HasOption(jsonEqOption) {
+			return nil
+		}
+
+		name := field.Name
+		opts = append(opts, cmp.FilterPath(func(p cmp.Path) bool {
+			if isCompareCodec(p) {
+				return false
+			}
+
+			for i := 1; i < len(p); i++ {
+				if sf, ok := p[i].(cmp.StructField); ok && sf.Name() == name && p[i-1].Type() == typ {
+					return true
+				}
+			}
+			return false
+		}, cmp.Comparer(equalRawMessages)))
+
+		return nil
+	})
+
+	return opts
+}
+
+func equalRawMessages(x, y json.RawMessage) bool {
+	var bx, by bytes.Buffer
+	if json.Compact(&bx, x) != nil || json.Compact(&by, y) != nil {
+		return bytes.Equal(x, y) // invalid JSON is compared as-is
+	}
+	return bytes.Equal(bx.Bytes(), by.Bytes())
+}
+
+// toRat converts v to an exact rational number, returning nil when v is not a
+// number (or is a json.Number which cannot be parsed).
+func toRat(v any) *big.Rat {
+	if v == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(v)
+
+	switch {
+	case value.Type() == jsonNumberType:
+		r, ok := new(big.Rat).SetString(value.String())
+		if !ok {
+			return nil
+		}
+		return r
+	case value.CanInt():
+		return new(big.Rat).SetInt64(value.Int())
+	case value.CanUint():
+		return new(big.Rat).SetUint64(value.Uint())
+	case value.CanFloat():
+		f := value.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil
+		}
+		return new(big.Rat).SetFloat64(f)
+	default:
+		return nil
+	}
+}
+
+// unexportedStructs returns a zero value of each struct type whose unexported
+// fields are ignored when comparing values of the same type as v (a pointer to
+// a struct), which is that struct type itself along with every struct type
+// reachable from fields using the "ignoreunexported" option, such as
+// `testdata:"output.json,ignoreunexported"`. Other nested types (eg: big.Int)
+// are left alone, since ignoring their unexported fields would make every
+// value compare equal.
+func unexportedStructs(v any) []any {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	seen := map[reflect.Type]bool{typ: true}
+
+	var structs []any
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).PkgPath != "" {
+			structs = append(structs, reflect.Zero(typ).Interface())
+			break
+		}
+	}
+
+	_ = walkFields(reflect.New(typ).Interface(), func(field reflect.StructField, _ reflect.Value, tag *structtag.Tag) error {
+		if tag.HasOption(ignoreUnexportedOption) {
+			collectUnexported(field.Type, seen, &structs)
+		}
+		return nil
+	})
+
+	return structs
+}
+
+const ignoreUnexportedOption = "ignoreunexported"
+
+// collectUnexported walks typ to find every struct type with unexported
+// fields, appending a zero value of each to structs. Types with an Equal
+// method (eg: time.Time) are skipped since cmp uses that method instead.
+func collectUnexported(typ reflect.Type, seen map[reflect.Type]bool, structs *[]any) {
+	if typ == nil || seen[typ] {
+		return
+	}
+	seen[typ] = true
+
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		collectUnexported(typ.Elem(), seen, structs)
+	case reflect.Map:
+		collectUnexported(typ.Key(), seen, structs)
+		collectUnexported(typ.Elem(), seen, structs)
+	case reflect.Struct:
+		if _, ok := typ.MethodByName("Equal"); ok {
+			return
+		}
+
+		var unexported bool
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" {
+				unexported = true
+			}
+			collectUnexported(field.Type, seen, structs)
+		}
+
+		if unexported {
+			*structs = append(*structs, reflect.Zero(typ).Interface())
+		}
+	}
+}
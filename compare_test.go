@@ -0,0 +1,296 @@
+package got
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertIgnoreKeys(t *testing.T) {
+	t.Run("raw json", func(t *testing.T) {
+		type test struct {
+			Output json.RawMessage `testdata:"output.json,ignorekeys=timestamp|meta.requestId"`
+		}
+
+		var mt mockT
+		Assert(&mt, "testdata/ignorekeys", &test{
+			Output: json.RawMessage(`{"id":1,"timestamp":"2024-01-01T00:00:00Z","meta":{"region":"us","requestId":"abc"}}`),
+		})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		type test struct {
+			Output map[string]any `testdata:"output.json,ignorekeys=timestamp|meta.requestId"`
+		}
+
+		var mt mockT
+		Assert(&mt, "testdata/ignorekeys", &test{
+			Output: map[string]any{
+				"id":        1,
+				"timestamp": "2024-01-01T00:00:00Z",
+				"meta": map[string]any{
+					"region":    "us",
+					"requestId": "abc",
+				},
+			},
+		})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		type test struct {
+			Output map[string]any `testdata:"output.json,ignorekeys=timestamp"`
+		}
+
+		var mt mockT
+		Assert(&mt, "testdata/ignorekeys", &test{
+			Output: map[string]any{
+				"id":        2,
+				"timestamp": "2024-01-01T00:00:00Z",
+				"meta":      map[string]any{"region": "us"},
+			},
+		})
+
+		require.True(t, mt.failed)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		type test struct {
+			Output map[string]any `testdata:"output.json,ignorekeys=timestamp|meta.requestId"`
+		}
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+		actual := &test{
+			Output: map[string]any{
+				"id":        1,
+				"timestamp": "2024-01-01T00:00:00Z",
+				"meta": map[string]any{
+					"region":    "us",
+					"requestId": "abc",
+				},
+			},
+		}
+
+		var mt mockT
+		Assert(&mt, dir, actual)
+		require.False(t, mt.failed, mt.logs)
+
+		// only the golden omits the keys, the actual value is left untouched
+		require.Contains(t, actual.Output, "timestamp")
+
+		expected, err := os.ReadFile("testdata/ignorekeys/output.json")
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, "output.json"))
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(data))
+	})
+}
+
+func TestAssertIgnoreKeysCodec(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "output.data"), []byte(`{"id":1,"timestamp":"2024-01-01T00:00:00Z"}`), 0644))
+
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	_, err := w.Write([]byte(`{"id":1,"timestamp":"2024-01-01T00:00:00Z"}`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "output.json.gz"), b.Bytes(), 0644))
+
+	t.Run("codec option", func(t *testing.T) {
+		type test struct {
+			Output map[string]any `testdata:"output.data,codec=json,ignorekeys=timestamp"`
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Output: map[string]any{"id": 1, "timestamp": "2025-06-30T12:34:56Z"}})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		type test struct {
+			Output map[string]any `testdata:"output.json.gz,gzip,ignorekeys=timestamp"`
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Output: map[string]any{"id": 1, "timestamp": "2025-06-30T12:34:56Z"}})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+}
+
+func TestAssertIgnoreKeysYAMLNode(t *testing.T) {
+	t.Cleanup(codec.Snapshot())
+	codec.Register(".yaml", &codec.YAMLCodec{PreserveComments: true})
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "output.yaml"), []byte("# the user\nid: 1\nmeta:\n  region: us\n"), 0644))
+
+	type test struct {
+		Output map[string]any `testdata:"output.yaml,ignorekeys=timestamp|meta.requestId"`
+	}
+
+	t.Run("match", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, dir, &test{
+			Output: map[string]any{
+				"id":        1,
+				"timestamp": "2024-01-01T00:00:00Z",
+				"meta":      map[string]any{"region": "us", "requestId": "abc"},
+			},
+		})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, dir, &test{
+			Output: map[string]any{
+				"id":        2,
+				"timestamp": "2024-01-01T00:00:00Z",
+				"meta":      map[string]any{"region": "us"},
+			},
+		})
+
+		require.True(t, mt.failed)
+	})
+}
+
+func TestAssertLooseNumbers(t *testing.T) {
+	type test struct {
+		Output map[string]any `testdata:"output.json"`
+	}
+
+	t.Run("equal", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "testdata/numbers", &test{
+			Output: map[string]any{
+				"int":    float64(42),
+				"float":  3.5,
+				"nested": []any{1, float32(2.25)},
+			},
+		})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("different", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "testdata/numbers", &test{
+			Output: map[string]any{
+				"int":    float64(43),
+				"float":  3.5,
+				"nested": []any{1, 2.25},
+			},
+		})
+
+		require.True(t, mt.failed)
+	})
+}
+
+func TestIgnoreFieldsGlobally(t *testing.T) {
+	type item struct {
+		Name      string `json:"name"`
+		CreatedAt string `json:"createdAt"`
+	}
+
+	type test struct {
+		Output struct {
+			Items     []item `json:"items"`
+			UpdatedAt string `json:"updatedAt"`
+		} `testdata:"output.json"`
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "output.json"), []byte(`{"items":[{"name":"a","createdAt":"2024-01-01"}],"updatedAt":"2024-01-02"}`), 0644))
+
+	actual := new(test)
+	actual.Output.Items = []item{{Name: "a", CreatedAt: "2025-06-01"}}
+	actual.Output.UpdatedAt = "2025-06-02"
+
+	t.Run("not ignored", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, dir, actual)
+
+		require.True(t, mt.failed)
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		t.Cleanup(IgnoreFieldsGlobally("CreatedAt", "UpdatedAt"))
+
+		var mt mockT
+		Assert(&mt, dir, actual)
+		require.False(t, mt.failed, mt.logs)
+
+		// other fields are still compared
+		changed := *actual
+		changed.Output.Items = []item{{Name: "b", CreatedAt: "2025-06-01"}}
+
+		mt = mockT{}
+		Assert(&mt, dir, &changed)
+		require.True(t, mt.failed)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		restoreOuter := IgnoreFieldsGlobally("CreatedAt")
+		restoreInner := IgnoreFieldsGlobally("CreatedAt", "UpdatedAt")
+
+		restoreInner()
+		require.Equal(t, map[string]bool{"CreatedAt": true}, globallyIgnoredFields())
+
+		restoreOuter()
+		require.Empty(t, globallyIgnoredFields())
+
+		var mt mockT
+		Assert(&mt, dir, actual)
+		require.True(t, mt.failed)
+	})
+}
+
+func TestUnexportedStructs(t *testing.T) {
+	type nested struct {
+		Value string
+		cache map[string]string
+	}
+
+	t.Run("top-level", func(t *testing.T) {
+		type test struct {
+			Input  nested `testdata:"input.json"`
+			loaded bool
+		}
+
+		require.Equal(t, []any{test{}}, unexportedStructs(new(test)))
+	})
+
+	t.Run("ignoreunexported", func(t *testing.T) {
+		type test struct {
+			Input []nested `testdata:"input.json,ignoreunexported"`
+		}
+
+		require.Equal(t, []any{nested{}}, unexportedStructs(new(test)))
+	})
+
+	t.Run("nested types are compared", func(t *testing.T) {
+		type test struct {
+			Input *big.Int `testdata:"input.json"`
+		}
+
+		require.Empty(t, unexportedStructs(new(test)))
+	})
+}
@@ -0,0 +1,132 @@
+package got
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/fatih/structtag"
+	"github.com/google/go-cmp/cmp"
+)
+
+// The "comparecodec" option compares a raw []byte field (eg: json.RawMessage)
+// by decoding both sides through the named codec (see codec.GetByName), so
+// differences in whitespace or key order are ignored while the contents are
+// still saved as-is (eg: `testdata:"body.json,comparecodec=json"`). Fields
+// using the "explode" option apply this to each of the files.
+const compareCodecOption = "comparecodec"
+
+// checkCompareCodecs ensures every field of input using the "comparecodec"
+// option is raw bytes and names a registered codec.
+func checkCompareCodecs(input any) error {
+	if input == nil || reflect.TypeOf(input).Kind() != reflect.Ptr || reflect.TypeOf(input).Elem().Kind() != reflect.Struct {
+		return nil // invalid inputs are reported elsewhere
+	}
+
+	return walkFields(input, func(field reflect.StructField, _ reflect.Value, tag *structtag.Tag) error {
+		if _, ok := getTagOption(tag, compareCodecOption); !ok {
+			return nil
+		}
+
+		if _, _, err := compareCodec(field, tag); err != nil {
+			return fmt.Errorf("%s.%s: %w", getTypeName(input), fieldName(field, tag), err)
+		}
+
+		return nil
+	})
+}
+
+// compareCodecOptions returns the cmp options which decode the fields of v (a
+// pointer to a struct) using the "comparecodec" option before comparing, along
+// with a filter matching the paths of those fields so that other options for
+// the same types can exclude them.
+func compareCodecOptions(v any) ([]cmp.Option, func(cmp.Path) bool) {
+	none := func(cmp.Path) bool { return false }
+
+	if v == nil || reflect.TypeOf(v).Kind() != reflect.Ptr || reflect.TypeOf(v).Elem().Kind() != reflect.Struct {
+		return nil, none
+	}
+
+	typ := reflect.TypeOf(v).Elem()
+
+	var opts []cmp.Option
+	var filters []func(cmp.Path) bool
+	_ = walkFields(reflect.New(typ).Interface(), func(field reflect.StructField, _ reflect.Value, tag *structtag.Tag) error {
+		if _, ok := getTagOption(tag, compareCodecOption); !ok {
+			return nil
+		}
+
+		c, raw, err := compareCodec(field, tag)
+		if err != nil {
+			return nil // invalid fields are reported by checkCompareCodecs
+		}
+
+		name := field.Name
+		filter := func(p cmp.Path) bool {
+			for i := 1; i < len(p); i++ {
+				if sf, ok := p[i].(cmp.StructField); ok && sf.Name() == name && p[i-1].Type() == typ {
+					return true
+				}
+			}
+			return false
+		}
+
+		filters = append(filters, filter)
+		opts = append(opts, cmp.FilterPath(filter, cmp.Transformer(compareCodecOption, decodeFunc(raw, c))))
+
+		return nil
+	})
+
+	if len(filters) == 0 {
+		return opts, none
+	}
+
+	return opts, func(p cmp.Path) bool {
+		for _, filter := range filters {
+			if filter(p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// compareCodec returns the codec named by the "comparecodec" option of field,
+// along with the raw type it applies to.
+func compareCodec(field reflect.StructField, tag *structtag.Tag) (codec.Codec, reflect.Type, error) {
+	name, _ := getTagOption(tag, compareCodecOption)
+
+	typ := field.Type
+	if isMap(typ) && tag.HasOption("explode") {
+		typ = typ.Elem()
+	}
+
+	if !isBytes(typ) {
+		return nil, nil, fmt.Errorf("comparecodec requires []byte, but got %s", typ)
+	}
+
+	c, err := codec.GetByName(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid comparecodec %q: %w", name, err)
+	}
+
+	return c, typ, nil
+}
+
+// decodeFunc returns a func(T) any for the raw type typ, as required by
+// cmp.Transformer, which decodes the contents using c. Contents which cannot
+// be decoded (eg: an empty golden file) are compared as a string instead.
+func decodeFunc(typ reflect.Type, c codec.Codec) any {
+	fn := reflect.FuncOf([]reflect.Type{typ}, []reflect.Type{reflect.TypeOf((*any)(nil)).Elem()}, false)
+
+	return reflect.MakeFunc(fn, func(args []reflect.Value) []reflect.Value {
+		data := args[0].Bytes()
+
+		var v any
+		if err := c.Unmarshal(data, &v); err != nil {
+			v = string(data)
+		}
+
+		return []reflect.Value{reflect.ValueOf(&v).Elem()}
+	}).Interface()
+}
@@ -0,0 +1,116 @@
+package got
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareCodec(t *testing.T) {
+	golden := "{\n  \"name\": \"a\",\n  \"tags\": [1, 2]\n}\n"
+	actual := []byte(`{"tags":[1,2],"name":"a"}`)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "body.json"), []byte(golden), 0644))
+
+	t.Run("bytes", func(t *testing.T) {
+		type test struct {
+			Body []byte `testdata:"body.json,comparecodec=json"`
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Body: actual})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("raw message", func(t *testing.T) {
+		type test struct {
+			Body json.RawMessage `testdata:"body.json,comparecodec=json"`
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Body: actual})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("explode", func(t *testing.T) {
+		type test struct {
+			Bodies map[string][]byte `testdata:"*.json,explode,comparecodec=json"`
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Bodies: map[string][]byte{"body.json": actual}})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		type test struct {
+			Body []byte `testdata:"body.json"`
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Body: actual})
+
+		require.True(t, mt.failed)
+	})
+
+	t.Run("different", func(t *testing.T) {
+		type test struct {
+			Body []byte `testdata:"body.json,comparecodec=json"`
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Body: []byte(`{"tags":[2,1],"name":"a"}`)})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "test of *got.test failed")
+	})
+
+	t.Run("saved as-is", func(t *testing.T) {
+		type test struct {
+			Body []byte `testdata:"body.json,comparecodec=json"`
+		}
+
+		dir := t.TempDir()
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		Assert(&mt, dir, &test{Body: actual})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "body.json"))
+		require.NoError(t, err)
+		require.Equal(t, string(actual), string(data))
+	})
+
+	t.Run("not bytes", func(t *testing.T) {
+		type test struct {
+			Body string `testdata:"body.json,comparecodec=json"`
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Body: string(actual)})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "*got.test.Body: comparecodec requires []byte, but got string")
+	})
+
+	t.Run("unknown codec", func(t *testing.T) {
+		type test struct {
+			Body []byte `testdata:"body.json,comparecodec=nope"`
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Body: actual})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], `*got.test.Body: invalid comparecodec "nope"`)
+	})
+}
@@ -0,0 +1,133 @@
+package got
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/fatih/structtag"
+)
+
+// The "concat" option allows multiple fields to share a single file (eg: a
+// human-readable report), where each field is one section of that file. The
+// option value determines the order of the sections, which are separated by a
+// line of "=" characters, such as:
+//
+//	Summary string `testdata:"report.txt,concat=1"`
+//	Details string `testdata:"report.txt,concat=2"`
+const concatOption = "concat"
+
+var concatSeparator = []byte("\n=====\n")
+
+// concatPart is the position of a field within a concatenated file.
+type concatPart struct {
+	index int
+	count int
+}
+
+// concatParts determines the position of each field of input using the
+// "concat" option within its file, keyed by the field name. Every field which
+// references a concatenated file must use the option with a unique order.
+func concatParts(input any) (map[string]*concatPart, error) {
+	type section struct {
+		field string
+		order int
+	}
+
+	var files []string
+	sections := make(map[string][]section)
+	plain := make(map[string]string)
+
+	err := walkFields(input, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		option, ok := getTagOption(tag, concatOption)
+		if !ok {
+			plain[tag.Name] = field.Name
+			return nil
+		}
+
+		if tag.HasOption("explode") {
+			return fmt.Errorf("%s.%s: concat cannot be used with explode", getTypeName(input), field.Name)
+		}
+
+		order, err := strconv.Atoi(option)
+		if err != nil {
+			return fmt.Errorf("%s.%s: invalid concat order %q", getTypeName(input), field.Name, option)
+		}
+
+		for _, s := range sections[tag.Name] {
+			if s.order == order {
+				return fmt.Errorf("%s.%s: concat order %d for file %q is already used by %s", getTypeName(input), field.Name, order, tag.Name, s.field)
+			}
+		}
+
+		if _, ok := sections[tag.Name]; !ok {
+			files = append(files, tag.Name)
+		}
+		sections[tag.Name] = append(sections[tag.Name], section{field: field.Name, order: order})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make(map[string]*concatPart)
+
+	for _, file := range files {
+		if name, ok := plain[file]; ok {
+			return nil, fmt.Errorf("%s.%s: file %q is concatenated, so it requires the concat option", getTypeName(input), name, file)
+		}
+
+		list := sections[file]
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].order < list[j].order
+		})
+
+		for i, s := range list {
+			parts[s.field] = &concatPart{index: i, count: len(list)}
+		}
+	}
+
+	return parts, nil
+}
+
+// splitConcat returns the section of data for part, which is empty when the
+// file has fewer sections than expected.
+func splitConcat(data []byte, part concatPart) []byte {
+	sections := bytes.SplitN(data, concatSeparator, part.count)
+	if part.index >= len(sections) {
+		return nil
+	}
+	return sections[part.index]
+}
+
+// joinConcat is the inverse of splitConcat, where a file with only empty
+// sections is empty itself. Since the separator is plain text (which is also
+// markdown for a heading), any section other than the last which contains it
+// (or ends with the start of it) is an error, as it would be split differently
+// when loaded.
+func joinConcat(sections [][]byte) ([]byte, error) {
+	empty := true
+	for _, section := range sections {
+		if len(section) > 0 {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return nil, nil
+	}
+
+	data := bytes.Join(sections, concatSeparator)
+
+	for i, section := range sections {
+		actual := splitConcat(data, concatPart{index: i, count: len(sections)})
+		if !bytes.Equal(actual, section) {
+			return nil, fmt.Errorf("section %d contains the concat separator %q", i+1, concatSeparator)
+		}
+	}
+
+	return data, nil
+}
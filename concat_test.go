@@ -0,0 +1,174 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcat(t *testing.T) {
+	// declared out of order to ensure the concat order is used
+	type test struct {
+		Details string `testdata:"report.txt,concat=2"`
+		Summary string `testdata:"report.txt,concat=1"`
+	}
+
+	t.Run("load", func(t *testing.T) {
+		testLoadOne(t, "concat", new(test), &test{
+			Summary: "All checks passed.",
+			Details: "- lint: ok\n- test: ok\n",
+		}, []string{
+			`[GoT] Load: *got.test.Details: loaded file "testdata/concat/report.txt" as string (size 22)`,
+			`[GoT] Load: *got.test.Summary: loaded file "testdata/concat/report.txt" as string (size 18)`,
+		})
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "testdata/concat", &test{
+			Summary: "All checks passed.",
+			Details: "- lint: ok\n- test: ok\n",
+		})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("compare mismatch", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "testdata/concat", &test{
+			Summary: "Some checks failed.",
+			Details: "- lint: ok\n- test: ok\n",
+		})
+
+		require.True(t, mt.failed)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		Assert(&mt, dir, &test{
+			Summary: "All checks passed.",
+			Details: "- lint: ok\n- test: ok\n",
+		})
+
+		require.False(t, mt.failed, mt.logs)
+		require.EqualValues(t, []string{
+			`[GoT] Assert: *got.test: saved file "` + filepath.Join(dir, "report.txt") + `" (size 47)`,
+		}, mt.logs)
+
+		actual, err := os.ReadFile(filepath.Join(dir, "report.txt"))
+		require.NoError(t, err)
+
+		expected, err := os.ReadFile("testdata/concat/report.txt")
+		require.NoError(t, err)
+
+		require.Equal(t, string(expected), string(actual))
+	})
+
+	t.Run("save separator", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		Assert(&mt, dir, &test{
+			Summary: "Report\n=====\n",
+			Details: "- lint: ok\n",
+		})
+
+		require.True(t, mt.failed)
+		require.EqualValues(t, []string{
+			`[GoT] Assert: *got.test error: failed to concat file "` + filepath.Join(dir, "report.txt") + `": section 1 contains the concat separator "\n=====\n"`,
+		}, mt.logs)
+	})
+
+	t.Run("missing option", func(t *testing.T) {
+		type test struct {
+			Summary string `testdata:"report.txt,concat=1"`
+			Details string `testdata:"report.txt"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/concat", new(test))
+
+		require.True(t, mt.failed)
+		require.EqualValues(t, []string{
+			`[GoT] Load: *got.test.Details: file "report.txt" is concatenated, so it requires the concat option`,
+		}, mt.logs)
+	})
+
+	t.Run("duplicate order", func(t *testing.T) {
+		type test struct {
+			Summary string `testdata:"report.txt,concat=1"`
+			Details string `testdata:"report.txt,concat=1"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/concat", new(test))
+
+		require.True(t, mt.failed)
+		require.EqualValues(t, []string{
+			`[GoT] Load: *got.test.Details: concat order 1 for file "report.txt" is already used by Summary`,
+		}, mt.logs)
+	})
+}
+
+func TestJoinConcat(t *testing.T) {
+	spec := []struct {
+		name     string
+		sections []string
+		expected string
+		err      string
+	}{
+		{name: "sections", sections: []string{"a", "b"}, expected: "a\n=====\nb"},
+		{name: "empty", sections: []string{"", ""}, expected: ""},
+		{name: "separator in last section", sections: []string{"a", "b\n=====\nc"}, expected: "a\n=====\nb\n=====\nc"},
+		{name: "separator in section", sections: []string{"a\n=====\nb", "c"}, err: `section 1 contains the concat separator "\n=====\n"`},
+		{name: "markdown heading", sections: []string{"Title\n=====", "c"}, err: `section 1 contains the concat separator "\n=====\n"`},
+	}
+
+	for _, s := range spec {
+		t.Run(s.name, func(t *testing.T) {
+			sections := make([][]byte, len(s.sections))
+			for i, section := range s.sections {
+				sections[i] = []byte(section)
+			}
+
+			actual, err := joinConcat(sections)
+			if s.err != "" {
+				require.EqualError(t, err, s.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, s.expected, string(actual))
+		})
+	}
+}
+
+func TestSplitConcat(t *testing.T) {
+	spec := []struct {
+		name     string
+		input    string
+		part     concatPart
+		expected string
+	}{
+		{name: "first", input: "a\n=====\nb", part: concatPart{index: 0, count: 2}, expected: "a"},
+		{name: "last", input: "a\n=====\nb", part: concatPart{index: 1, count: 2}, expected: "b"},
+		{name: "extra separators", input: "a\n=====\nb\n=====\nc", part: concatPart{index: 1, count: 2}, expected: "b\n=====\nc"},
+		{name: "missing section", input: "a", part: concatPart{index: 1, count: 2}, expected: ""},
+	}
+
+	for _, s := range spec {
+		t.Run(s.name, func(t *testing.T) {
+			require.Equal(t, s.expected, string(splitConcat([]byte(s.input), s.part)))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package got
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/fatih/structtag"
+)
+
+// configFile is an optional file within a directory which declares defaults
+// for every field loaded from or saved to that directory, so the conventions
+// for a set of fixtures live alongside them rather than on each struct tag
+// (eg: {"indent": 4, "text": true}).
+const configFile = ".gotconfig"
+
+// dirConfig is the contents of a configFile, where each setting is overridden
+// by the equivalent struct tag option.
+type dirConfig struct {
+	// Indent is the default indentation for codecs which implement
+	// codec.Indenter, as with the "indent" option.
+	Indent *int `json:"indent"`
+
+	// Strict rejects fields which are not defined by the struct being decoded
+	// into, as with Options.StrictLoad (but for Assert too).
+	Strict bool `json:"strict"`
+
+	// Text gives every field the "text" option, so text files are saved with a
+	// trailing newline.
+	Text bool `json:"text"`
+}
+
+// readDirConfig reads the configFile in dir, where a missing file is the same
+// as an empty one.
+func readDirConfig(fsys fileSystem, dir string) (dirConfig, error) {
+	var config dirConfig
+
+	file := filepath.Join(dir, configFile)
+
+	data, err := readFS(fsys, file)
+	if os.IsNotExist(err) {
+		return config, nil
+	} else if err != nil {
+		return config, fmt.Errorf("failed to read config %s: %w", file, err)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&config); err != nil {
+		return config, fmt.Errorf("failed to decode config %s: %w", file, err)
+	}
+
+	if config.Indent != nil && *config.Indent < 0 {
+		return config, fmt.Errorf("invalid indent %d in config %s", *config.Indent, file)
+	}
+
+	return config, nil
+}
+
+// withDirConfig returns a copy of o using the defaults in the configFile for
+// dir.
+func (o Options) withDirConfig(dir string) (Options, error) {
+	config, err := readDirConfig(o.files(), dir)
+	if err != nil {
+		return o, err
+	}
+
+	o.config = config
+	if config.Strict {
+		o.strict = true
+	}
+
+	return o, nil
+}
+
+// newPipeline is the same as the package-level newPipeline, with the defaults
+// from the configFile applied.
+func (o Options) newPipeline(tag *structtag.Tag) pipeline {
+	p := newPipeline(tag)
+	if o.config.Text {
+		p.text = true
+	}
+	return p
+}
+
+// fieldPipeline is the same as the package-level fieldPipeline, with the
+// defaults from the configFile applied.
+func (o Options) fieldPipeline(field reflect.StructField) pipeline {
+	p := fieldPipeline(field)
+	if o.config.Text {
+		p.text = true
+	}
+	return p
+}
+
+// withConfigIndent applies the default indent from the configFile to a copy of
+// c when it supports indentation, which the "indent" option then overrides.
+func (o Options) withConfigIndent(c codec.Codec) codec.Codec {
+	if o.config.Indent == nil {
+		return c
+	}
+
+	if _, ok := c.(codec.Indenter); !ok {
+		return c
+	}
+
+	c = copyCodec(c)
+	c.(codec.Indenter).SetIndent(*o.config.Indent)
+	return c
+}
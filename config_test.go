@@ -0,0 +1,142 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirConfig(t *testing.T) {
+	type testObject struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		type test struct {
+			Output testObject `testdata:"output.json"`
+		}
+
+		dir := t.TempDir()
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		Assert(&mt, dir, &test{Output: testObject{Name: "a"}})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "output.json"))
+		require.NoError(t, err)
+		require.Equal(t, "{\n  \"name\": \"a\"\n}", string(data))
+	})
+
+	t.Run("indent", func(t *testing.T) {
+		type test struct {
+			Output   testObject `testdata:"output.json"`
+			Override testObject `testdata:"override.json,indent=0"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, configFile), []byte(`{"indent": 4}`), 0644))
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		Assert(&mt, dir, &test{Output: testObject{Name: "a"}, Override: testObject{Name: "b"}})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "output.json"))
+		require.NoError(t, err)
+		require.Equal(t, "{\n    \"name\": \"a\"\n}", string(data))
+
+		data, err = os.ReadFile(filepath.Join(dir, "override.json"))
+		require.NoError(t, err)
+		require.Equal(t, `{"name":"b"}`, string(data))
+	})
+
+	t.Run("text", func(t *testing.T) {
+		type test struct {
+			Output string `testdata:"output.txt"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, configFile), []byte(`{"text": true}`), 0644))
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		Assert(&mt, dir, &test{Output: "hello"})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "output.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello\n", string(data))
+
+		var actual test
+		Load(&mt, dir, &actual)
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "hello", actual.Output)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		type test struct {
+			Input testObject `testdata:"input.json"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, configFile), []byte(`{"strict": true}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "input.json"), []byte(`{"name":"input","extra":true}`), 0644))
+
+		var mt mockT
+		Load(&mt, dir, new(test))
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], `json: unknown field "extra"`)
+	})
+
+	t.Run("only applies to its own dir", func(t *testing.T) {
+		type test struct {
+			Input testObject `testdata:"input.json"`
+		}
+
+		strict := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(strict, configFile), []byte(`{"strict": true}`), 0644))
+
+		lenient := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(lenient, "input.json"), []byte(`{"name":"input","extra":true}`), 0644))
+
+		var mt mockT
+		var actual test
+		LoadDirs(&mt, []string{strict, lenient}, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "input", actual.Input.Name)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, configFile), []byte(`{"indnet": 4}`), 0644))
+
+		var mt mockT
+		Load(&mt, dir, new(struct {
+			Input string `testdata:"input.txt"`
+		}))
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], `failed to decode config `+filepath.Join(dir, configFile)+`: json: unknown field "indnet"`)
+	})
+
+	t.Run("negative indent", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, configFile), []byte(`{"indent": -1}`), 0644))
+
+		var mt mockT
+		Load(&mt, dir, new(struct {
+			Input string `testdata:"input.txt"`
+		}))
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "invalid indent -1 in config")
+	})
+}
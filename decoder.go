@@ -0,0 +1,44 @@
+package got
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TestdataDecoder can be implemented by an output type to take full control
+// of how it is loaded from an input directory, such as for a fixture which is
+// spread across multiple files in a structured way. Load calls DecodeTestdata
+// for each input directory (in order) after populating any fields with a
+// "testdata" struct tag, so both approaches can be combined. Types which are
+// not structs are loaded via DecodeTestdata alone.
+//
+// There is no counterpart for saving, so Assert can compare these types but
+// only their tagged fields are updated with "-update-golden".
+type TestdataDecoder interface {
+	DecodeTestdata(dir string) error
+}
+
+// decodeTestdata calls DecodeTestdata on output for each of the inputs, when
+// it implements TestdataDecoder.
+func decodeTestdata(log *logger, inputs []string, output any) error {
+	d, ok := output.(TestdataDecoder)
+	if !ok {
+		return nil
+	}
+
+	for _, input := range inputs {
+		if err := d.DecodeTestdata(input); err != nil {
+			return fmt.Errorf("%s: DecodeTestdata error: %w", getTypeName(output), err)
+		}
+		log.Log("decoded dir %q via DecodeTestdata", input)
+	}
+
+	return nil
+}
+
+// isDecoderOnly reports whether output is loaded via DecodeTestdata alone,
+// since it is not a struct and so has no fields to populate.
+func isDecoderOnly(output any) bool {
+	_, ok := output.(TestdataDecoder)
+	return ok && reflect.TypeOf(output).Elem().Kind() != reflect.Struct
+}
@@ -0,0 +1,113 @@
+package got
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// table is a CSV fixture split across a header file and a rows file.
+type table struct {
+	Name   string `testdata:"name.txt"`
+	Header []string
+	Rows   [][]string
+}
+
+func (t *table) DecodeTestdata(dir string) error {
+	header, err := os.ReadFile(filepath.Join(dir, "header.csv"))
+	if err != nil {
+		return err
+	}
+
+	rows, err := os.ReadFile(filepath.Join(dir, "rows.csv"))
+	if err != nil {
+		return err
+	}
+
+	t.Header = strings.Split(strings.TrimSpace(string(header)), ",")
+	t.Rows = nil
+	for _, row := range strings.Split(strings.TrimSpace(string(rows)), "\n") {
+		t.Rows = append(t.Rows, strings.Split(row, ","))
+	}
+
+	return nil
+}
+
+// lines is not a struct, so it is only loaded via DecodeTestdata.
+type lines []string
+
+func (l *lines) DecodeTestdata(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, "lines.txt"))
+	if err != nil {
+		return err
+	}
+
+	*l = strings.Split(strings.TrimSpace(string(data)), "\n")
+	return nil
+}
+
+type failingDecoder struct{}
+
+func (f *failingDecoder) DecodeTestdata(dir string) error {
+	return errors.New("bespoke failure")
+}
+
+func TestLoadTestdataDecoder(t *testing.T) {
+	expected := table{
+		Name:   "orders",
+		Header: []string{"id", "total"},
+		Rows:   [][]string{{"1", "10"}, {"2", "20"}},
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		testLoadOne(t, "decoder", new(table), &expected, []string{
+			`[GoT] Load: *got.table.Name: loaded file "testdata/decoder/name.txt" as string (size 6)`,
+			`[GoT] Load: *got.table: decoded dir "testdata/decoder" via DecodeTestdata`,
+		})
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		testLoadOne(t, "decoder", new(lines), &lines{"a", "b", "c"}, []string{
+			`[GoT] Load: *got.lines: decoded dir "testdata/decoder" via DecodeTestdata`,
+		})
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var mt mockT
+		Load(&mt, "testdata/decoder", new(failingDecoder))
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				`[GoT] Load: *got.failingDecoder: DecodeTestdata error: bespoke failure`,
+			},
+		}, mt)
+	})
+
+	t.Run("only", func(t *testing.T) {
+		var mt mockT
+		var actual table
+		LoadOnly(&mt, "testdata/decoder", []string{"Name"}, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, table{Name: "orders"}, actual)
+	})
+
+	t.Run("assert", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "testdata/decoder", &expected)
+		require.False(t, mt.failed, mt.logs)
+
+		changed := expected
+		changed.Rows = [][]string{{"1", "10"}}
+
+		mt = mockT{}
+		Assert(&mt, "testdata/decoder", &changed)
+		require.True(t, mt.failed)
+	})
+}
@@ -0,0 +1,22 @@
+package got
+
+import (
+	"strings"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+var dumpConfig = spew.ConfigState{
+	Indent:                  "  ",
+	SortKeys:                true,
+	DisablePointerAddresses: true,
+	DisableCapacities:       true,
+}
+
+// Dump renders v (eg: a struct populated by Load) in an indented format which
+// includes the types of the values, for logging while debugging a test (eg:
+// `t.Log(got.Dump(input))`). Map keys are sorted and pointer addresses are
+// omitted, so the output is stable across runs.
+func Dump(v any) string {
+	return strings.TrimSuffix(dumpConfig.Sdump(v), "\n")
+}
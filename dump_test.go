@@ -0,0 +1,51 @@
+package got
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	type item struct {
+		Name string
+		Tags []string
+	}
+
+	type test struct {
+		Input  *item
+		Output map[string]int
+		Raw    []byte
+	}
+
+	v := &test{
+		Input:  &item{Name: "a", Tags: []string{"x", "y"}},
+		Output: map[string]int{"b": 2, "a": 1, "c": 3},
+		Raw:    []byte("hi"),
+	}
+
+	expected := `(*got.test)({
+  Input: (*got.item)({
+    Name: (string) (len=1) "a",
+    Tags: ([]string) (len=2) {
+      (string) (len=1) "x",
+      (string) (len=1) "y"
+    }
+  }),
+  Output: (map[string]int) (len=3) {
+    (string) (len=1) "a": (int) 1,
+    (string) (len=1) "b": (int) 2,
+    (string) (len=1) "c": (int) 3
+  },
+  Raw: ([]uint8) (len=2) {
+    00000000  68 69                                             |hi|
+  }
+})`
+
+	require.Equal(t, expected, Dump(v))
+
+	// repeated to ensure the map keys are always sorted
+	for i := 0; i < 10; i++ {
+		require.Equal(t, expected, Dump(v))
+	}
+}
@@ -0,0 +1,158 @@
+package got
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fatih/structtag"
+)
+
+// AssertExactFiles ensures that dir contains exactly the files for the fields of
+// value (a pointer to a struct annotated with the "testdata" struct tag), which
+// is useful for tightly controlled fixtures. Fields using the "explode" option
+// expect a file for each of the keys in the map, while fields with zero values
+// expect no file at all (matching what Assert saves).
+//
+// The test fails listing any files which are missing or unexpected. The
+// contents of the files are not checked, use Assert for that.
+func AssertExactFiles(t tester, dir string, value any) {
+	t.Helper()
+
+	if err := assertExactFiles(dir, value); err != nil {
+		t.Fatalf("[GoT] AssertExactFiles: %s", err.Error())
+	}
+}
+
+func assertExactFiles(dir string, value any) error {
+	if value == nil {
+		return errors.New("value cannot be nil")
+	}
+
+	if k := reflect.TypeOf(value).Kind(); k != reflect.Ptr {
+		return fmt.Errorf("value must be a pointer, but got %s", k)
+	}
+
+	expected, err := expectedFiles(dir, value)
+	if err != nil {
+		return err
+	}
+
+	actual, err := listFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, file := range sortedKeys(expected) {
+		if !actual[file] {
+			failures = append(failures, fmt.Sprintf("missing file %q", file))
+		}
+	}
+	for _, file := range sortedKeys(actual) {
+		if !expected[file] {
+			failures = append(failures, fmt.Sprintf("unexpected file %q", file))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("files in %s do not match %s:\n%s", dir, getTypeName(value), strings.Join(failures, "\n"))
+	}
+
+	return nil
+}
+
+// expectedFiles returns the relative paths (using "/") of the files that would
+// be saved for value within dir.
+func expectedFiles(dir string, value any) (map[string]bool, error) {
+	files := make(map[string]bool)
+
+	err := walkDirFields(dir, value, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		if value.IsZero() || isRemote(tag.Name) {
+			return nil
+		}
+
+		if isExplodeSlice(field.Type, tag) {
+			names, err := explodeSliceFiles(tag.Name, value.Len())
+			if err != nil {
+				return err
+			}
+
+			for i, name := range names {
+				if !value.Index(i).IsZero() {
+					files[filepath.ToSlash(name)] = true
+				}
+			}
+
+			return nil
+		}
+
+		if isMap(field.Type) && tag.HasOption("explode") {
+			for _, key := range value.MapKeys() {
+				if !value.MapIndex(key).IsZero() {
+					files[filepath.ToSlash(explodeFile("", tag, key.String()))] = true
+				}
+			}
+
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, platformFile(osFS, dir, alternateName(osFS, dir, testdataFile(field, tag, value))))
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// listFiles returns the relative paths (using "/") of all the files in dir,
+// excluding the manifest and config which are not fixtures themselves.
+func listFiles(dir string) (map[string]bool, error) {
+	files := make(map[string]bool)
+
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+
+		if rel != manifestFile && rel != configFile {
+			files[filepath.ToSlash(rel)] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", dir, err)
+	}
+
+	return files, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertExactFiles(t *testing.T) {
+	type test struct {
+		Input    []string          `testdata:"input.json"`
+		Expected map[string]string `testdata:"expected/*.txt,explode"`
+	}
+
+	t.Run("match", func(t *testing.T) {
+		var mt mockT
+		AssertExactFiles(&mt, "testdata/multiple-nested", &test{
+			Input:    []string{"a", "b"},
+			Expected: map[string]string{"expected/a.txt": "A", "expected/b.txt": "B"},
+		})
+
+		require.EqualValues(t, mockT{helper: true}, mt)
+	})
+
+	t.Run("extra file", func(t *testing.T) {
+		var mt mockT
+		AssertExactFiles(&mt, "testdata/multiple-nested", &test{
+			Input:    []string{"a", "b"},
+			Expected: map[string]string{"expected/a.txt": "A"},
+		})
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"[GoT] AssertExactFiles: files in testdata/multiple-nested do not match *got.test:\n" +
+					`unexpected file "expected/b.txt"`,
+			},
+		}, mt)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		type test struct {
+			Input    []string          `testdata:"input.json"`
+			Output   string            `testdata:"output.txt"`
+			Expected map[string]string `testdata:"expected/*.txt,explode"`
+		}
+
+		var mt mockT
+		AssertExactFiles(&mt, "testdata/multiple-nested", &test{
+			Input:    []string{"a", "b"},
+			Output:   "hello world",
+			Expected: map[string]string{"expected/a.txt": "A", "expected/b.txt": "B", "expected/c.txt": "C"},
+		})
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"[GoT] AssertExactFiles: files in testdata/multiple-nested do not match *got.test:\n" +
+					`missing file "expected/c.txt"` + "\n" +
+					`missing file "output.txt"`,
+			},
+		}, mt)
+	})
+
+	t.Run("platform fallback", func(t *testing.T) {
+		type test struct {
+			Expected string `testdata:"expected_{goos}.txt"`
+		}
+
+		setPlatform(t, "linux", "amd64")
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "expected.txt"), []byte("default"), 0644))
+
+		var mt mockT
+		AssertExactFiles(&mt, dir, &test{Expected: "default"})
+
+		require.EqualValues(t, mockT{helper: true}, mt)
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		type test struct {
+			Input    []string          `testdata:"input.json"`
+			Schema   string            `testdata:"https://registry.test/schema.json"`
+			Expected map[string]string `testdata:"expected/*.txt,explode"`
+		}
+
+		var mt mockT
+		AssertExactFiles(&mt, "testdata/multiple-nested", &test{
+			Input:    []string{"a", "b"},
+			Schema:   "{}",
+			Expected: map[string]string{"expected/a.txt": "A", "expected/b.txt": "B"},
+		})
+
+		require.EqualValues(t, mockT{helper: true}, mt)
+	})
+
+	t.Run("nil value", func(t *testing.T) {
+		var mt mockT
+		AssertExactFiles(&mt, "testdata/multiple-nested", nil)
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs:   []string{"[GoT] AssertExactFiles: value cannot be nil"},
+		}, mt)
+	})
+}
@@ -0,0 +1,107 @@
+package got
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/structtag"
+)
+
+// The "explode" option can also be used with a slice of raw values (ie:
+// [][]byte or []string), which is populated with the contents of each matching
+// file in sorted filename order (eg: `testdata:"frames/*.bin,explode"`). When
+// saving, the "*" in the pattern is replaced with the zero-padded index of each
+// element (eg: "frames/0.bin", "frames/1.bin"), so the order is preserved when
+// the files are loaded again.
+func isExplodeSlice(typ reflect.Type, tag *structtag.Tag) bool {
+	if typ.Kind() != reflect.Slice || isBytes(typ) || !tag.HasOption("explode") {
+		return false
+	}
+
+	return isBytes(typ.Elem()) || isString(typ.Elem())
+}
+
+// explodeSliceFiles returns the name of the file for each of the n elements of
+// a slice using the "explode" option, based on the pattern from the struct tag.
+func explodeSliceFiles(pattern string, n int) ([]string, error) {
+	if strings.Count(pattern, "*") != 1 || strings.ContainsAny(pattern, "?[") {
+		return nil, fmt.Errorf("explode into a slice requires a pattern with a single *, but got %q", pattern)
+	}
+
+	width := len(strconv.Itoa(n - 1))
+
+	files := make([]string, n)
+	for i := range files {
+		files[i] = strings.Replace(pattern, "*", fmt.Sprintf("%0*d", width, i), 1)
+	}
+
+	return files, nil
+}
+
+func (o Options) loadExplodeSlice(log *logger, input, pattern string, opts fileOptions, tag *structtag.Tag, value reflect.Value) error {
+	matches, err := globFiles(o.files(), log, input, pattern, tag)
+	if err != nil {
+		return fmt.Errorf("failed to list files %s: %w", pattern, err)
+	}
+
+	if len(matches) == 0 {
+		log.Missing("no matches found")
+		return nil
+	}
+
+	sort.Strings(matches)
+
+	// a later dir with matches replaces the slice entirely, since there is no
+	// key to merge the elements by
+	slice := reflect.MakeSlice(value.Type(), len(matches), len(matches))
+	for i, match := range matches {
+		if err := o.loadFile(log.WithPrefix("["+strconv.Itoa(i)+"]"), match, opts, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	value.Set(slice)
+	return nil
+}
+
+func (o Options) saveExplodeSlice(log *logger, dir string, tag *structtag.Tag, field reflect.StructField, value reflect.Value) error {
+	files, err := explodeSliceFiles(tag.Name, value.Len())
+	if err != nil {
+		return err
+	}
+
+	// remove the files of any elements beyond the end of the slice, which
+	// would otherwise be loaded again
+	stale, err := filepath.Glob(filepath.Join(dir, tag.Name))
+	if err != nil {
+		return err
+	}
+
+	saved := make(map[string]bool, len(files))
+	for i, file := range files {
+		file = filepath.Join(dir, file)
+		saved[file] = true
+
+		if err := o.saveFile(log, file, field, value.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range stale {
+		if saved[file] || o.plan != nil {
+			continue
+		}
+
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete file %s: %w", file, err)
+		}
+		log.Log("removed file %q: stale", file)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplodeSlice(t *testing.T) {
+	t.Run("load bytes", func(t *testing.T) {
+		type test struct {
+			Frames [][]byte `testdata:"frames/*.bin,explode"`
+		}
+
+		testLoadOne(t, "explodeslice", new(test), &test{
+			Frames: [][]byte{{0, 1, 2}, {0xff, 0xfe}, {0x10}},
+		}, []string{
+			`[GoT] Load: *got.test.Frames[0]: loaded file "testdata/explodeslice/frames/a.bin" as bytes (size 3)`,
+			`[GoT] Load: *got.test.Frames[1]: loaded file "testdata/explodeslice/frames/b.bin" as bytes (size 2)`,
+			`[GoT] Load: *got.test.Frames[2]: loaded file "testdata/explodeslice/frames/c.bin" as bytes (size 1)`,
+		})
+	})
+
+	t.Run("load strings", func(t *testing.T) {
+		type test struct {
+			Lines []string `testdata:"lines/*.txt,explode"`
+		}
+
+		testLoadOne(t, "explodeslice", new(test), &test{
+			Lines: []string{"first", "second"},
+		}, []string{
+			`[GoT] Load: *got.test.Lines[0]: loaded file "testdata/explodeslice/lines/1.txt" as string (size 5)`,
+			`[GoT] Load: *got.test.Lines[1]: loaded file "testdata/explodeslice/lines/2.txt" as string (size 6)`,
+		})
+	})
+
+	t.Run("load without matches", func(t *testing.T) {
+		type test struct {
+			Frames [][]byte `testdata:"missing/*.bin,explode"`
+		}
+
+		testLoadOne(t, "explodeslice", new(test), &test{}, []string{
+			`[GoT] Load: *got.test.Frames: no matches found`,
+		})
+	})
+
+	t.Run("save", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		type test struct {
+			Frames [][]byte `testdata:"frames/*.bin,explode"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "frames"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "frames", "stale.bin"), []byte("stale"), 0644))
+
+		frames := make([][]byte, 11)
+		for i := range frames {
+			frames[i] = []byte{byte(i)}
+		}
+
+		var mt mockT
+		Assert(&mt, dir, &test{Frames: frames})
+		require.False(t, mt.failed, mt.logs)
+
+		entries, err := os.ReadDir(filepath.Join(dir, "frames"))
+		require.NoError(t, err)
+		require.Len(t, entries, 11)
+		require.Equal(t, "00.bin", entries[0].Name())
+		require.Equal(t, "10.bin", entries[10].Name())
+
+		updateGolden = false
+
+		var actual test
+		Load(&mt, dir, &actual)
+		require.Equal(t, frames, actual.Frames)
+
+		mt = mockT{}
+		Assert(&mt, dir, &test{Frames: frames})
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		type test struct {
+			Frames [][]byte `testdata:"frames/*/*.bin,explode"`
+		}
+
+		var mt mockT
+		Assert(&mt, t.TempDir(), &test{Frames: [][]byte{{1}}})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], `explode into a slice requires a pattern with a single *, but got "frames/*/*.bin"`)
+	})
+}
@@ -0,0 +1,105 @@
+package got
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/fatih/structtag"
+)
+
+// FieldInfo describes a struct field with a "testdata" struct tag.
+type FieldInfo struct {
+	// Name is the name of the struct field.
+	Name string
+
+	// Type is the type of the struct field.
+	Type reflect.Type
+
+	// File is the filename from the struct tag, relative to the input dir. For
+	// explode fields, this is a glob pattern.
+	File string
+
+	// Options are the additional options from the struct tag.
+	Options []string
+
+	// Explode indicates the field uses the "explode" option, either with a map
+	// or a slice of raw values (eg: [][]byte).
+	Explode bool
+}
+
+// WalkFields calls fn for each field with a "testdata" struct tag in v, which
+// is a struct or a pointer to one, without loading any data. This is useful for
+// building tools (eg: documentation, coverage) around test fixtures.
+func WalkFields(v any, fn func(FieldInfo)) error {
+	if v == nil {
+		return errors.New("input cannot be nil")
+	}
+
+	typ := reflect.TypeOf(v)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("input must be a struct, but got %s", typ.Kind())
+	}
+
+	return walkFields(reflect.New(typ).Interface(), func(field reflect.StructField, _ reflect.Value, tag *structtag.Tag) error {
+		fn(FieldInfo{
+			Name:    field.Name,
+			Type:    field.Type,
+			File:    tag.Name,
+			Options: tag.Options,
+			Explode: isExplodeSlice(field.Type, tag) || (isMap(field.Type) && tag.HasOption("explode")),
+		})
+
+		return nil
+	})
+}
+
+// fieldTag returns the "testdata" struct tag for field, if it has one. Invalid
+// struct tags are ignored since they are reported by walkFields.
+func fieldTag(field reflect.StructField) (*structtag.Tag, bool) {
+	tags, err := structtag.Parse(string(field.Tag))
+	if err != nil {
+		return nil, false
+	}
+
+	tag, err := tags.Get(tagName)
+	if err != nil {
+		return nil, false
+	}
+
+	return tag, true
+}
+
+// walkFields calls fn for each field with a usable "testdata" struct tag in the
+// struct that input points to.
+func walkFields(input any, fn func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error) error {
+	typ := reflect.TypeOf(input).Elem()
+	val := reflect.ValueOf(input).Elem()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		value := val.Field(i)
+
+		tags, err := structtag.Parse(string(field.Tag))
+		if err != nil {
+			return fmt.Errorf("%s.%s: failed to parse struct tags: %w", getTypeName(input), field.Name, err)
+		}
+
+		tag, err := tags.Get(tagName)
+		if err != nil {
+			continue
+		} else if tag.Name == "" || tag.Name == "-" {
+			continue
+		}
+
+		if err := fn(field, value, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package got
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkFields(t *testing.T) {
+	type test struct {
+		Input    map[string]any    `testdata:"input.json"`
+		Expected map[string]string `testdata:"expected/*.txt,explode,strip=expected/"`
+		Frames   [][]byte          `testdata:"frames/*.bin,explode"`
+		Ignored  string            `testdata:"-"`
+		Missing  string
+	}
+
+	expected := []FieldInfo{
+		{
+			Name: "Input",
+			Type: reflect.TypeOf(map[string]any{}),
+			File: "input.json",
+		},
+		{
+			Name:    "Expected",
+			Type:    reflect.TypeOf(map[string]string{}),
+			File:    "expected/*.txt",
+			Options: []string{"explode", "strip=expected/"},
+			Explode: true,
+		},
+		{
+			Name:    "Frames",
+			Type:    reflect.TypeOf([][]byte{}),
+			File:    "frames/*.bin",
+			Options: []string{"explode"},
+			Explode: true,
+		},
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		var actual []FieldInfo
+		require.NoError(t, WalkFields(test{}, func(info FieldInfo) {
+			actual = append(actual, info)
+		}))
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		var actual []FieldInfo
+		require.NoError(t, WalkFields(new(test), func(info FieldInfo) {
+			actual = append(actual, info)
+		}))
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		require.EqualError(t, WalkFields(nil, func(FieldInfo) {}), "input cannot be nil")
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		require.EqualError(t, WalkFields(new(int), func(FieldInfo) {}), "input must be a struct, but got int")
+	})
+
+	t.Run("invalid struct tag", func(t *testing.T) {
+		type test struct {
+			Invalid string `this is not valid`
+		}
+
+		require.EqualError(t, WalkFields(test{}, func(FieldInfo) {}), "*got.test.Invalid: failed to parse struct tags: bad syntax for struct tag pair")
+	})
+}
@@ -0,0 +1,72 @@
+package got
+
+import (
+	"reflect"
+
+	"github.com/fatih/structtag"
+)
+
+// TestdataFiler can be implemented by a field type to choose the file it is
+// loaded from and saved to, overriding the name in the struct tag (and any
+// manifest), such as for an image type which is always saved as a ".png"
+// file. The name is relative to the directory, like the struct tag.
+//
+// When saving, TestdataFile is called on the value being saved (or a zero
+// value when it is a nil pointer). When loading there is no value yet, so it
+// is called on a zero value instead, which means the name should not depend
+// on the contents of the value or the saved file will not be found again.
+type TestdataFiler interface {
+	TestdataFile() string
+}
+
+var testdataFilerType = reflect.TypeOf((*TestdataFiler)(nil)).Elem()
+
+// testdataFile returns the file name for field, which is the result of
+// TestdataFile when the field type implements TestdataFiler (with either a
+// value or a pointer receiver) and otherwise the name in tag. The method is
+// called on value, or a zero value when value is invalid (eg: when loading).
+func testdataFile(field reflect.StructField, tag *structtag.Tag, value reflect.Value) string {
+	if field.Type == nil || tag.HasOption("explode") {
+		return tag.Name
+	}
+
+	filer, ok := testdataFiler(field.Type, value)
+	if !ok {
+		return tag.Name
+	}
+
+	if name := filer.TestdataFile(); name != "" {
+		return name
+	}
+
+	return tag.Name
+}
+
+// testdataFiler returns value (of type typ) as a TestdataFiler, using a zero
+// value when value is invalid, unusable or a nil pointer. For a pointer type,
+// the zero value points to a zero value of the element type rather than nil.
+func testdataFiler(typ reflect.Type, value reflect.Value) (TestdataFiler, bool) {
+	if !typ.Implements(testdataFilerType) && !reflect.PtrTo(typ).Implements(testdataFilerType) {
+		return nil, false
+	}
+
+	if !value.IsValid() || !value.CanInterface() {
+		value = reflect.Zero(typ)
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value = reflect.New(typ.Elem())
+		}
+
+		filer, ok := value.Interface().(TestdataFiler)
+		return filer, ok
+	}
+
+	// a copy is addressable, so pointer receivers work too
+	p := reflect.New(typ)
+	p.Elem().Set(value)
+
+	filer, ok := p.Interface().(TestdataFiler)
+	return filer, ok
+}
@@ -0,0 +1,130 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testImage []byte
+
+func (testImage) TestdataFile() string { return "image.png" }
+
+type testConfig struct {
+	Name string `yaml:"name"`
+}
+
+func (*testConfig) TestdataFile() string { return "config.yaml" }
+
+// testVersioned chooses its file from its contents, which only works when
+// saving since loading has no contents yet.
+type testVersioned struct {
+	Version string `json:"version"`
+}
+
+func (v testVersioned) TestdataFile() string {
+	if v.Version == "" {
+		return ""
+	}
+	return "v" + v.Version + ".json"
+}
+
+func TestTestdataFiler(t *testing.T) {
+	type test struct {
+		Image  testImage  `testdata:"image.bin"`
+		Config testConfig `testdata:"config.json"`
+	}
+
+	t.Run("load", func(t *testing.T) {
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/filer", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, testImage("PNG"), actual.Image)
+		require.Equal(t, testConfig{Name: "filer"}, actual.Config)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		dir := t.TempDir()
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		Assert(&mt, dir, &test{Image: testImage("PNG"), Config: testConfig{Name: "filer"}})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "image.png"))
+		require.NoError(t, err)
+		require.Equal(t, "PNG", string(data))
+
+		data, err = os.ReadFile(filepath.Join(dir, "config.yaml"))
+		require.NoError(t, err)
+		require.Equal(t, "name: filer\n", string(data))
+
+		_, err = os.Stat(filepath.Join(dir, "image.bin"))
+		require.True(t, os.IsNotExist(err))
+
+		_, err = os.Stat(filepath.Join(dir, "config.json"))
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("assert", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "testdata/filer", &test{Image: testImage("PNG"), Config: testConfig{Name: "filer"}})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+}
+
+func TestTestdataFilerPointer(t *testing.T) {
+	type test struct {
+		Config *testConfig `testdata:"config.json"`
+	}
+
+	t.Run("load", func(t *testing.T) {
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/filer", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, &testConfig{Name: "filer"}, actual.Config)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		dir := t.TempDir()
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		Assert(&mt, dir, &test{Config: &testConfig{Name: "filer"}})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "config.yaml"))
+		require.NoError(t, err)
+		require.Equal(t, "name: filer\n", string(data))
+	})
+}
+
+func TestTestdataFilerValue(t *testing.T) {
+	type test struct {
+		Output testVersioned `testdata:"output.json"`
+	}
+
+	dir := t.TempDir()
+
+	var mt mockT
+	SetUpdateGolden(true)
+	defer SetUpdateGolden(false)
+	Assert(&mt, dir, &test{Output: testVersioned{Version: "2"}})
+	require.False(t, mt.failed, mt.logs)
+
+	data, err := os.ReadFile(filepath.Join(dir, "v2.json"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version": "2"}`, string(data))
+
+	_, err = os.Stat(filepath.Join(dir, "output.json"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,171 @@
+package got
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/fatih/structtag"
+)
+
+var formatters = newTransformers()
+
+// RegisterFormatter adds a formatter for files with the extension ext (eg: a
+// ".go" formatter wrapping go/format.Source), which is applied to the contents
+// of golden files when they are saved as well as when they are loaded.
+//
+// During Assert, raw (string and []byte) values are also formatted before
+// comparing, so formatting differences alone never cause a test to fail.
+//
+// The returned func restores the formatter that was previously registered for
+// ext (if any), such as `t.Cleanup(got.RegisterFormatter(".go", fn))`.
+func RegisterFormatter(ext string, fn func([]byte) ([]byte, error)) func() {
+	return formatters.register(ext, fn)
+}
+
+func formatData(file string, data []byte) ([]byte, error) {
+	return formatters.apply(file, data)
+}
+
+// formatValues returns a shallow copy of input (a pointer to a struct) where
+// the raw fields with a registered formatter have been formatted.
+func formatValues(input any) (any, error) {
+	if formatters.empty() {
+		return input, nil
+	}
+
+	return transformValues(input, "format", formatData)
+}
+
+// transformValues returns a shallow copy of input (a pointer to a struct) where
+// fn has been applied to the raw fields, given the name of the file for each.
+// The action describes fn in any errors (eg: "format").
+func transformValues(input any, action string, fn func(file string, data []byte) ([]byte, error)) (any, error) {
+	if input == nil || reflect.TypeOf(input).Kind() != reflect.Ptr || reflect.TypeOf(input).Elem().Kind() != reflect.Struct {
+		return input, nil // invalid inputs are reported elsewhere
+	}
+
+	typ := reflect.TypeOf(input).Elem()
+	output := reflect.New(typ)
+	output.Elem().Set(reflect.ValueOf(input).Elem())
+
+	err := walkFields(output.Interface(), func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		if value.IsZero() {
+			return nil
+		}
+
+		if isMap(field.Type) && tag.HasOption("explode") {
+			m := reflect.MakeMapWithSize(field.Type, value.Len())
+			for _, key := range value.MapKeys() {
+				val := reflect.New(field.Type.Elem()).Elem()
+				val.Set(value.MapIndex(key))
+
+				if err := transformValue(explodeFile("", tag, key.String()), val, action, fn); err != nil {
+					return fmt.Errorf("%s.%s: %w", getTypeName(input), fieldName(field, tag), err)
+				}
+
+				m.SetMapIndex(key, val)
+			}
+			value.Set(m)
+
+			return nil
+		}
+
+		if err := transformValue(firstAlternate(testdataFile(field, tag, value)), value, action, fn); err != nil {
+			return fmt.Errorf("%s.%s: %w", getTypeName(input), fieldName(field, tag), err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Interface(), nil
+}
+
+func transformValue(file string, value reflect.Value, action string, fn func(file string, data []byte) ([]byte, error)) error {
+	if isJSONUnmarshaler(value.Type()) {
+		return nil
+	}
+
+	switch {
+	case isBytes(value.Type()):
+		data, err := fn(file, value.Bytes())
+		if err != nil {
+			return fmt.Errorf("failed to %s %q: %w", action, file, err)
+		}
+		value.SetBytes(data)
+	case isString(value.Type()):
+		data, err := fn(file, []byte(value.String()))
+		if err != nil {
+			return fmt.Errorf("failed to %s %q: %w", action, file, err)
+		}
+		value.SetString(string(data))
+	}
+
+	return nil
+}
+
+// transformers is a registry of funcs which transform the contents of files by
+// their extension (eg: formatters and normalizers), which is safe for
+// concurrent use.
+type transformers struct {
+	mu  sync.RWMutex
+	fns map[string]func([]byte) ([]byte, error)
+}
+
+func newTransformers() *transformers {
+	return &transformers{fns: make(map[string]func([]byte) ([]byte, error))}
+}
+
+// register adds fn for ext, returning a func which restores the previous one.
+func (r *transformers) register(ext string, fn func([]byte) ([]byte, error)) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev, existed := r.fns[ext]
+	r.fns[ext] = fn
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if existed {
+			r.fns[ext] = prev
+		} else {
+			delete(r.fns, ext)
+		}
+	}
+}
+
+// apply transforms data using the func registered for the extension of file,
+// returning data as-is when there is none or it is empty.
+func (r *transformers) apply(file string, data []byte) ([]byte, error) {
+	r.mu.RLock()
+	fn, ok := r.fns[filepath.Ext(file)]
+	r.mu.RUnlock()
+
+	if !ok || len(data) == 0 {
+		return data, nil
+	}
+
+	return fn(data)
+}
+
+// has reports whether there is a func registered for the extension of file.
+func (r *transformers) has(file string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.fns[filepath.Ext(file)]
+	return ok
+}
+
+func (r *transformers) empty() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.fns) == 0
+}
@@ -0,0 +1,107 @@
+package got
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFormatter(t *testing.T) {
+	t.Cleanup(RegisterFormatter(".upper", func(data []byte) ([]byte, error) {
+		return bytes.ToUpper(data), nil
+	}))
+	t.Cleanup(RegisterFormatter(".invalid", func(data []byte) ([]byte, error) {
+		return nil, errors.New("invalid syntax")
+	}))
+
+	type test struct {
+		Input string            `testdata:"input.upper"`
+		Files map[string][]byte `testdata:"*.upper,explode"`
+	}
+
+	t.Run("save", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		Assert(&mt, dir, &test{Input: "hello world"})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "input.upper"))
+		require.NoError(t, err)
+		require.Equal(t, "HELLO WORLD", string(data))
+	})
+
+	t.Run("load", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "input.upper"), []byte("hello world"), 0644))
+
+		var mt mockT
+		var actual test
+		Load(&mt, dir, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "HELLO WORLD", actual.Input)
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "input.upper"), []byte("HELLO WORLD"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.upper"), []byte("a"), 0644))
+
+		var mt mockT
+		Assert(&mt, dir, &test{
+			Input: "hello world",
+			Files: map[string][]byte{"input.upper": []byte("hello world"), "a.upper": []byte("A")},
+		})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.invalid"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "input.invalid"), []byte("hello world"), 0644))
+
+		var mt mockT
+		Load(&mt, dir, new(test))
+
+		require.True(t, mt.failed)
+		require.Len(t, mt.logs, 1)
+		require.Contains(t, mt.logs[0], `input.invalid" format error: invalid syntax`)
+	})
+}
+
+func TestRegisterFormatterRestore(t *testing.T) {
+	lower := func(data []byte) ([]byte, error) { return bytes.ToLower(data), nil }
+	upper := func(data []byte) ([]byte, error) { return bytes.ToUpper(data), nil }
+
+	restoreLower := RegisterFormatter(".case", lower)
+	restoreUpper := RegisterFormatter(".case", upper)
+
+	data, err := formatData("input.case", []byte("Hello"))
+	require.NoError(t, err)
+	require.Equal(t, "HELLO", string(data))
+
+	restoreUpper()
+
+	data, err = formatData("input.case", []byte("Hello"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	restoreLower()
+
+	data, err = formatData("input.case", []byte("Hello"))
+	require.NoError(t, err)
+	require.Equal(t, "Hello", string(data))
+	require.True(t, formatters.empty())
+}
@@ -0,0 +1,114 @@
+package got
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/fatih/structtag"
+)
+
+// The "frontmatter" and "body" options split a file with a YAML front matter
+// block (delimited by "---" lines) into 2 fields which both reference the same
+// file, such as:
+//
+//	Header Header `testdata:"post.md,frontmatter"`
+//	Body   string `testdata:"post.md,body"`
+const (
+	frontMatterHeader = "frontmatter"
+	frontMatterBody   = "body"
+)
+
+var frontMatterDelimiter = []byte("---")
+
+// frontMatterPart returns which part of a front matter file the field uses,
+// which is empty for regular fields.
+func frontMatterPart(tag *structtag.Tag) string {
+	switch {
+	case tag.HasOption(frontMatterHeader):
+		return frontMatterHeader
+	case tag.HasOption(frontMatterBody):
+		return frontMatterBody
+	default:
+		return ""
+	}
+}
+
+// splitFrontMatter separates the YAML header from the body of data. When data
+// does not start with a front matter block, the entire contents are the body.
+func splitFrontMatter(data []byte) ([]byte, []byte) {
+	first, rest, ok := cutLine(data)
+	if !ok || !bytes.Equal(bytes.TrimSpace(first), frontMatterDelimiter) {
+		return nil, data
+	}
+
+	var header []byte
+	for len(rest) > 0 {
+		var line []byte
+		line, rest, _ = cutLine(rest)
+
+		if bytes.Equal(bytes.TrimSpace(line), frontMatterDelimiter) {
+			return header, rest
+		}
+
+		header = append(header, line...)
+	}
+
+	return nil, data // unterminated, so treat it as a regular file
+}
+
+// cutLine splits data after the first newline, including it with the line.
+func cutLine(data []byte) ([]byte, []byte, bool) {
+	i := bytes.IndexByte(data, '\n')
+	if i < 0 {
+		return data, nil, false
+	}
+	return data[:i+1], data[i+1:], true
+}
+
+// joinFrontMatter is the inverse of splitFrontMatter.
+func joinFrontMatter(header, body []byte) []byte {
+	if len(header) == 0 {
+		return body
+	}
+
+	var b bytes.Buffer
+	b.Write(frontMatterDelimiter)
+	b.WriteByte('\n')
+	b.Write(header)
+	if !bytes.HasSuffix(header, []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	b.Write(frontMatterDelimiter)
+	b.WriteByte('\n')
+	b.Write(body)
+	return b.Bytes()
+}
+
+// frontMatterFile accumulates the parts of a front matter file to be saved.
+type frontMatterFile struct {
+	header []byte
+	body   []byte
+}
+
+// encodeFrontMatterPart encodes the value for one part of a front matter file,
+// where the header is always YAML and the body is the raw contents.
+func (o Options) encodeFrontMatterPart(file, part string, field reflect.StructField, value reflect.Value) ([]byte, error) {
+	if part == frontMatterBody || value.IsZero() {
+		data, _, err := o.encode(file, field, value)
+		return data, err
+	}
+
+	c, err := codec.Get(".yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.Marshal(value.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode front matter: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,114 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrontMatter(t *testing.T) {
+	type header struct {
+		Title string   `yaml:"title"`
+		Tags  []string `yaml:"tags"`
+	}
+
+	t.Run("load", func(t *testing.T) {
+		type test struct {
+			Header header `testdata:"post.md,frontmatter"`
+			Body   string `testdata:"post.md,body"`
+		}
+
+		testLoadOne(t, "frontmatter", new(test), &test{
+			Header: header{Title: "Hello World", Tags: []string{"a", "b"}},
+			Body:   "# Hello\n\nThis is the body.\n",
+		}, []string{
+			`[GoT] Load: *got.test.Header: loaded file "testdata/frontmatter/post.md" as YAML (size 41)`,
+			`[GoT] Load: *got.test.Body: loaded file "testdata/frontmatter/post.md" as string (size 27)`,
+		})
+	})
+
+	t.Run("load without front matter", func(t *testing.T) {
+		type test struct {
+			Header header `testdata:"plain.md,frontmatter"`
+			Body   string `testdata:"plain.md,body"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/frontmatter", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.EqualValues(t, test{Body: "# Plain\n"}, actual)
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		type test struct {
+			Header header `testdata:"post.md,frontmatter"`
+			Body   string `testdata:"post.md,body"`
+		}
+
+		var mt mockT
+		Assert(&mt, "testdata/frontmatter", &test{
+			Header: header{Title: "Hello World", Tags: []string{"a", "b"}},
+			Body:   "# Hello\n\nThis is the body.\n",
+		})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		type test struct {
+			Header header `testdata:"post.md,frontmatter"`
+			Body   string `testdata:"post.md,body"`
+		}
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		Assert(&mt, dir, &test{
+			Header: header{Title: "Hello World", Tags: []string{"a", "b"}},
+			Body:   "# Hello\n\nThis is the body.\n",
+		})
+
+		require.False(t, mt.failed, mt.logs)
+		require.EqualValues(t, []string{
+			`[GoT] Assert: *got.test: saved file "` + filepath.Join(dir, "post.md") + `" (size 76)`,
+		}, mt.logs)
+
+		actual, err := os.ReadFile(filepath.Join(dir, "post.md"))
+		require.NoError(t, err)
+
+		expected, err := os.ReadFile("testdata/frontmatter/post.md")
+		require.NoError(t, err)
+
+		require.Equal(t, string(expected), string(actual))
+	})
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	spec := []struct {
+		name   string
+		input  string
+		header string
+		body   string
+	}{
+		{name: "header and body", input: "---\na: b\n---\nbody\n", header: "a: b\n", body: "body\n"},
+		{name: "header only", input: "---\na: b\n---\n", header: "a: b\n", body: ""},
+		{name: "no header", input: "body\n", header: "", body: "body\n"},
+		{name: "unterminated", input: "---\na: b\n", header: "", body: "---\na: b\n"},
+		{name: "windows line endings", input: "---\r\na: b\r\n---\r\nbody\r\n", header: "a: b\r\n", body: "body\r\n"},
+	}
+
+	for _, s := range spec {
+		t.Run(s.name, func(t *testing.T) {
+			header, body := splitFrontMatter([]byte(s.input))
+			require.Equal(t, s.header, string(header))
+			require.Equal(t, s.body, string(body))
+		})
+	}
+}
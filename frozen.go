@@ -0,0 +1,59 @@
+package got
+
+import (
+	"reflect"
+
+	"github.com/fatih/structtag"
+	"github.com/google/go-cmp/cmp"
+)
+
+// The "frozen" option protects a hand-maintained golden file from being
+// overwritten when updating golden files, where the field is compared against
+// the existing file instead (eg: `testdata:"expected.txt,frozen"`).
+const frozenOption = "frozen"
+
+// frozenFields returns the names of the fields of input using the "frozen"
+// option, which includes remote files since they cannot be updated either.
+func frozenFields(input any) []string {
+	var names []string
+
+	_ = walkFields(input, func(field reflect.StructField, _ reflect.Value, tag *structtag.Tag) error {
+		if tag.HasOption(frozenOption) || isRemote(tag.Name) {
+			names = append(names, field.Name)
+		}
+		return nil
+	})
+
+	return names
+}
+
+// assertFrozen compares the frozen fields of actual against dir, which is done
+// when updating golden files since those fields are not saved.
+func (o Options) assertFrozen(log *logger, dir string, actual any) error {
+	names := frozenFields(actual)
+	if len(names) == 0 {
+		return nil
+	}
+
+	typ := reflect.TypeOf(actual).Elem()
+
+	expected := reflect.New(typ).Interface()
+	o.only = names
+	if err := o.loadDirs(log, []string{dir}, expected); err != nil {
+		return err
+	}
+
+	// only the frozen fields are compared, as the others were just saved
+	frozen := reflect.New(typ)
+	for _, name := range names {
+		frozen.Elem().FieldByName(name).Set(reflect.ValueOf(actual).Elem().FieldByName(name))
+	}
+
+	opts := compareOptions(actual)
+
+	if !cmp.Equal(expected, frozen.Interface(), opts...) {
+		return &diffError{typ: getTypeName(expected), diff: cmp.Diff(expected, frozen.Interface(), opts...)}
+	}
+
+	return nil
+}
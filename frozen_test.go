@@ -0,0 +1,67 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrozen(t *testing.T) {
+	type test struct {
+		Generated string `testdata:"generated.txt"`
+		Curated   string `testdata:"curated.txt,frozen"`
+	}
+
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "generated.txt"), []byte("old"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "curated.txt"), []byte("curated"), 0644))
+		return dir
+	}
+
+	readFile := func(t *testing.T, file string) string {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("update skips frozen", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := setup(t)
+
+		var mt mockT
+		Assert(&mt, dir, &test{Generated: "new", Curated: "curated"})
+
+		require.False(t, mt.failed, mt.logs)
+		require.Contains(t, mt.logs, "[GoT] Assert: *got.test.Curated: skipped: field is frozen")
+		require.Equal(t, "new", readFile(t, filepath.Join(dir, "generated.txt")))
+		require.Equal(t, "curated", readFile(t, filepath.Join(dir, "curated.txt")))
+	})
+
+	t.Run("update still compares frozen", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := setup(t)
+
+		var mt mockT
+		Assert(&mt, dir, &test{Generated: "new", Curated: "changed"})
+
+		require.True(t, mt.failed)
+		require.Equal(t, "new", readFile(t, filepath.Join(dir, "generated.txt")))
+		require.Equal(t, "curated", readFile(t, filepath.Join(dir, "curated.txt")))
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		dir := setup(t)
+
+		var mt mockT
+		Assert(&mt, dir, &test{Generated: "old", Curated: "changed"})
+
+		require.True(t, mt.failed)
+	})
+}
@@ -0,0 +1,320 @@
+package got
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// fileSystem is the source of the files read while loading, which is the OS
+// unless the files come from an archive (see LoadTar and LoadGitRef). Names
+// are the same paths used in logs and errors (eg: "testdata/input.json").
+type fileSystem interface {
+	Open(name string) (fs.File, error)
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+}
+
+// osFS reads files from the OS, which is the default fileSystem.
+var osFS fileSystem = osFileSystem{}
+
+type osFileSystem struct{}
+
+func (osFileSystem) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// files returns the fileSystem that o loads from.
+func (o Options) files() fileSystem {
+	if o.fsys == nil {
+		return osFS
+	}
+	return o.fsys
+}
+
+// readFS is the same as os.ReadFile, but reads from fsys.
+func readFS(fsys fileSystem, name string) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// dirCache is a fileSystem which reads each directory of the wrapped one at
+// most once, remembering the entries of each listing so isDir can answer from
+// them rather than calling Stat for every file. It is not safe for concurrent
+// use, and is only meant to last for a single glob.
+type dirCache struct {
+	fileSystem
+	dirs    map[string][]fs.DirEntry
+	entries map[string]fs.DirEntry
+}
+
+func newDirCache(fsys fileSystem) *dirCache {
+	return &dirCache{
+		fileSystem: fsys,
+		dirs:       make(map[string][]fs.DirEntry),
+		entries:    make(map[string]fs.DirEntry),
+	}
+}
+
+func (c *dirCache) ReadDir(name string) ([]fs.DirEntry, error) {
+	if entries, ok := c.dirs[name]; ok {
+		return entries, nil
+	}
+
+	entries, err := c.fileSystem.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.dirs[name] = entries
+	for _, entry := range entries {
+		c.entries[filepath.Join(name, entry.Name())] = entry
+	}
+
+	return entries, nil
+}
+
+// isDir reports whether name is a directory, using the entry from a listing
+// which has already been read when possible.
+func (c *dirCache) isDir(name string) (bool, error) {
+	// symlinks are followed by Stat, unlike the type from a listing
+	if entry, ok := c.entries[name]; ok && entry.Type()&fs.ModeSymlink == 0 {
+		return entry.IsDir(), nil
+	}
+
+	info, err := c.fileSystem.Stat(name)
+	if err != nil {
+		return false, err
+	}
+
+	return info.IsDir(), nil
+}
+
+// glob is the same as filepath.Glob, but lists directories using fsys.
+func glob(fsys fileSystem, pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	if !hasMeta(pattern) {
+		if _, err := fsys.Stat(pattern); err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+
+	dir, file := filepath.Split(pattern)
+	dir = cleanGlobPath(dir)
+
+	if !hasMeta(dir) {
+		return globDir(fsys, dir, file, nil), nil
+	}
+
+	// prevent infinite recursion
+	if dir == pattern {
+		return nil, filepath.ErrBadPattern
+	}
+
+	dirs, err := glob(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, d := range dirs {
+		matches = globDir(fsys, d, file, matches)
+	}
+
+	return matches, nil
+}
+
+// globDir appends the entries of dir which match pattern to matches, ignoring
+// any I/O errors (as filepath.Glob does).
+func globDir(fsys fileSystem, dir, pattern string, matches []string) []string {
+	if info, err := fsys.Stat(dir); err != nil || !info.IsDir() {
+		return matches
+	}
+
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return matches
+	}
+
+	for _, entry := range entries {
+		if ok, _ := filepath.Match(pattern, entry.Name()); ok {
+			matches = append(matches, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return matches
+}
+
+// globTree is the same as glob, but a "**" path segment matches any number of
+// directories (including none), where a trailing "**" matches every regular
+// file under that directory. A "**" which is only part of a segment (eg:
+// "a**") is an error, since it would otherwise behave like "*".
+func globTree(fsys fileSystem, pattern string) ([]string, error) {
+	sep := string(filepath.Separator)
+	segments := strings.Split(pattern, sep)
+
+	for _, segment := range segments {
+		if segment != "**" && strings.Contains(segment, "**") {
+			return nil, fmt.Errorf("** must be an entire path segment, but got %q", segment)
+		}
+
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	// the leading segments without any patterns are the root to search
+	var i int
+	for i < len(segments) && !hasMeta(segments[i]) {
+		i++
+	}
+
+	root := strings.Join(segments[:i], sep)
+	if root == "" && strings.HasPrefix(pattern, sep) {
+		root = sep
+	} else if root == "" {
+		root = "."
+	}
+
+	seen := make(map[string]bool)
+	var matches []string
+	err := globSegments(fsys, root, segments[i:], func(match string) {
+		if !seen[match] {
+			seen[match] = true
+			matches = append(matches, match)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// globSegments calls fn for each file or directory within dir matching the
+// remaining segments of a pattern (see globTree).
+func globSegments(fsys fileSystem, dir string, segments []string, fn func(string)) error {
+	if len(segments) == 0 {
+		if _, err := fsys.Stat(dir); err == nil {
+			fn(dir)
+		}
+		return nil
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	if segment == "**" && len(rest) == 0 {
+		files, err := walkFiles(fsys, dir, nil)
+		for _, file := range files {
+			fn(file)
+		}
+		return err
+	}
+
+	if segment == "**" {
+		// match no directories, followed by each directory in turn
+		if err := globSegments(fsys, dir, rest, fn); err != nil {
+			return err
+		}
+	} else if !hasMeta(segment) {
+		return globSegments(fsys, filepath.Join(dir, segment), rest, fn)
+	}
+
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return nil // missing (or unreadable) directories have no matches
+	}
+
+	for _, entry := range entries {
+		file := filepath.Join(dir, entry.Name())
+
+		if segment == "**" {
+			if entry.IsDir() {
+				if err := globSegments(fsys, file, segments, fn); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if ok, _ := filepath.Match(segment, entry.Name()); !ok {
+			continue
+		}
+
+		if len(rest) == 0 {
+			fn(file)
+		} else if entry.IsDir() {
+			if err := globSegments(fsys, file, rest, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkFiles appends every regular file under root (recursively) to matches,
+// where a missing root has no files.
+func walkFiles(fsys fileSystem, root string, matches []string) ([]string, error) {
+	entries, err := fsys.ReadDir(root)
+	if os.IsNotExist(err) {
+		return matches, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		file := filepath.Join(root, entry.Name())
+
+		if entry.IsDir() {
+			if matches, err = walkFiles(fsys, file, matches); err != nil {
+				return nil, err
+			}
+		} else if entry.Type().IsRegular() {
+			matches = append(matches, file)
+		}
+	}
+
+	return matches, nil
+}
+
+// cleanGlobPath prepares dir (from filepath.Split) for matching.
+func cleanGlobPath(dir string) string {
+	switch dir {
+	case "":
+		return "."
+	case string(filepath.Separator):
+		return dir
+	default:
+		return dir[:len(dir)-1]
+	}
+}
+
+func hasMeta(path string) bool {
+	magicChars := `*?[\`
+	if runtime.GOOS == "windows" {
+		magicChars = `*?[`
+	}
+	return strings.ContainsAny(path, magicChars)
+}
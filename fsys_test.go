@@ -0,0 +1,59 @@
+package got
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingFS is a fileSystem which counts the calls to the OS.
+type countingFS struct {
+	fileSystem
+	stats    int
+	readDirs map[string]int
+}
+
+func (c *countingFS) Stat(name string) (fs.FileInfo, error) {
+	c.stats++
+	return c.fileSystem.Stat(name)
+}
+
+func (c *countingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	c.readDirs[name]++
+	return c.fileSystem.ReadDir(name)
+}
+
+func TestDirCache(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	for _, name := range []string{"a.txt", "b.txt", "sub/c.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+
+	counter := &countingFS{fileSystem: osFS, readDirs: make(map[string]int)}
+	cache := newDirCache(counter)
+
+	matches, err := glob(cache, filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	require.Len(t, matches, 3)
+
+	counter.stats = 0
+	for _, match := range matches {
+		isDir, err := cache.isDir(match)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Base(match) == "sub", isDir)
+	}
+	require.Zero(t, counter.stats, "listed entries should not be stat-ed")
+
+	_, err = cache.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{dir: 1}, counter.readDirs)
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := cache.isDir(filepath.Join(dir, "missing"))
+		require.True(t, os.IsNotExist(err))
+	})
+}
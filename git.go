@@ -0,0 +1,121 @@
+package got
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// LoadGitRef is the same as Load, but the contents of dir are read as they
+// existed at the given git ref (eg: a branch, tag or commit) rather than from
+// the working tree.
+//
+// This is useful for comparing current outputs against golden files from an
+// earlier commit, such as catching unintended drift in a pull request. The git
+// executable must be available on the PATH.
+func LoadGitRef(t tester, ref, dir string, values ...any) {
+	t.Helper()
+
+	log := &logger{
+		t:      t,
+		prefix: "[GoT] Load: ",
+	}
+
+	if err := loadGitRef(log, ref, dir, values...); err != nil {
+		t.Fatalf("[GoT] LoadGitRef: %s", err.Error())
+	}
+}
+
+func loadGitRef(log *logger, ref, dir string, values ...any) error {
+	root, prefix, err := gitPrefix(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s at %s: %w", dir, ref, err)
+	}
+
+	// "<ref>:<prefix>" resolves to the tree for dir itself, so the archive
+	// contains paths relative to dir, which are named the same way in logs
+	fsys, err := gitArchive(root, ref+":"+prefix, ref+":"+filepath.Clean(dir))
+	if err != nil {
+		return fmt.Errorf("failed to read %s at %s: %w", dir, ref, err)
+	}
+
+	return Options{fsys: fsys}.loadDirs(log, []string{fsys.root}, values...)
+}
+
+// gitArchive reads the tree-ish from the repository at repo into memory, which
+// is streamed from "git archive" rather than being buffered or extracted.
+func gitArchive(repo, treeish, root string) (*archiveFS, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "archive", "--format=tar", treeish)
+	cmd.Dir = repo
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	fsys, err := readArchive(root, stdout)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, err
+	}
+
+	// drain any padding after the end of the archive
+	if _, err := io.Copy(io.Discard, stdout); err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, gitError(err, &stderr)
+	}
+
+	return fsys, nil
+}
+
+// gitPrefix returns the root of the repository containing dir, along with the
+// path of dir relative to that root.
+func gitPrefix(dir string) (string, string, error) {
+	out, err := git(dir, "rev-parse", "--show-toplevel", "--show-prefix")
+	if err != nil {
+		return "", "", err
+	}
+
+	lines := strings.SplitN(strings.TrimRight(string(out), "\n"), "\n", 2)
+	if len(lines) == 1 {
+		return lines[0], "", nil
+	}
+
+	return lines[0], lines[1], nil
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, gitError(err, &stderr)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// gitError returns the message git wrote to stderr for err, if any.
+func gitError(err error, stderr *bytes.Buffer) error {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return errors.New(msg)
+	}
+	return err
+}
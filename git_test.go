@@ -0,0 +1,98 @@
+package got
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git executable not found")
+	}
+
+	type test struct {
+		Input    string            `testdata:"input.txt"`
+		Expected map[string]string `testdata:"expected/*.txt,explode"`
+	}
+
+	repo := t.TempDir()
+	dir := filepath.Join(repo, "testdata")
+
+	runGit(t, repo, "init", "-q")
+	writeFiles(t, dir, map[string]string{
+		"input.txt":      "hello world",
+		"expected/a.txt": "HELLO",
+	})
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "-q", "-m", "first")
+	runGit(t, repo, "tag", "v1")
+
+	writeFiles(t, dir, map[string]string{
+		"input.txt":      "goodbye world",
+		"expected/a.txt": "GOODBYE",
+	})
+	runGit(t, repo, "commit", "-q", "-a", "-m", "second")
+
+	t.Run("tag", func(t *testing.T) {
+		var mt mockT
+		var actual test
+		LoadGitRef(&mt, "v1", dir, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.EqualValues(t, test{
+			Input:    "hello world",
+			Expected: map[string]string{"expected/a.txt": "HELLO"},
+		}, actual)
+		require.Equal(t, []string{
+			`[GoT] Load: *got.test.Input: loaded file "v1:` + dir + `/input.txt" as string (size 11)`,
+			`[GoT] Load: *got.test.Expected["expected/a.txt"]: loaded file "v1:` + dir + `/expected/a.txt" as string (size 5)`,
+		}, mt.logs)
+	})
+
+	t.Run("head", func(t *testing.T) {
+		var mt mockT
+		var actual test
+		LoadGitRef(&mt, "HEAD", dir, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.EqualValues(t, test{
+			Input:    "goodbye world",
+			Expected: map[string]string{"expected/a.txt": "GOODBYE"},
+		}, actual)
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		var mt mockT
+		LoadGitRef(&mt, "does-not-exist", dir, new(test))
+
+		require.True(t, mt.failed)
+		require.Len(t, mt.logs, 1)
+		require.True(t, strings.HasPrefix(mt.logs[0], "[GoT] LoadGitRef: failed to read "+dir+" at does-not-exist"), mt.logs[0])
+	})
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	args = append([]string{"-c", "user.name=got", "-c", "user.email=got@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+	}
+}
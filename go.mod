@@ -3,13 +3,17 @@ module github.com/dominicbarnes/got/v2
 go 1.18
 
 require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/davecgh/go-spew v1.1.0
 	github.com/fatih/structtag v1.2.0
+	github.com/fxamacker/cbor/v2 v2.7.0
 	github.com/google/go-cmp v0.6.0
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
 	github.com/stretchr/testify v1.3.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 )
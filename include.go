@@ -0,0 +1,151 @@
+package got
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dominicbarnes/got/v2/codec"
+)
+
+// The "include" option resolves include directives within a JSON file before
+// it is decoded, where any object of the form {"$include": "shared/a.json"} is
+// replaced by the contents of that file. The path is relative to the input
+// directory of the file, and when loading from multiple directories (eg: with
+// a SharedDir) the other directories are tried in turn, starting with the last.
+// Included files can include other files, as long as there is no cycle.
+const includeOption = "include"
+
+const includeKey = "$include"
+
+// includer reads included files from the first of dirs that has them.
+type includer struct {
+	fsys fileSystem
+	dirs []string
+}
+
+// includeDirs returns the dirs that files included by a file within input are
+// read from, which is input itself followed by the rest of inputs in reverse.
+func includeDirs(input string, inputs []string) []string {
+	dirs := []string{input}
+	for i := len(inputs) - 1; i >= 0; i-- {
+		if inputs[i] != input {
+			dirs = append(dirs, inputs[i])
+		}
+	}
+	return dirs
+}
+
+// checkInclude ensures c (the codec for a field using the "include" option)
+// decodes plain JSON, since that is the only format include directives are
+// resolved in.
+func checkInclude(c codec.Codec) error {
+	if _, ok := c.(*codec.JSONCodec); !ok {
+		return fmt.Errorf("include requires the JSON codec, but got %s", c.Name())
+	}
+	return nil
+}
+
+// resolveIncludes returns data (the contents of file) with all the include
+// directives replaced.
+func (i includer) resolveIncludes(file string, data []byte) ([]byte, error) {
+	v, err := decodeInclude(data)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := filepath.Rel(i.dirs[0], file)
+	if err != nil {
+		name = file
+	}
+
+	v, err = i.inlineIncludes(v, []string{filepath.ToSlash(name)})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
+}
+
+func decodeInclude(data []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber() // avoid losing precision when re-encoding
+
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// inlineIncludes walks v replacing include directives, where stack is the list
+// of files currently being included (to detect cycles).
+func (i includer) inlineIncludes(v any, stack []string) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		if name, ok := v[includeKey].(string); ok && len(v) == 1 {
+			return i.includeFile(name, stack)
+		}
+
+		for key, item := range v {
+			resolved, err := i.inlineIncludes(item, stack)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = resolved
+		}
+	case []any:
+		for n, item := range v {
+			resolved, err := i.inlineIncludes(item, stack)
+			if err != nil {
+				return nil, err
+			}
+			v[n] = resolved
+		}
+	}
+
+	return v, nil
+}
+
+func (i includer) includeFile(name string, stack []string) (any, error) {
+	for _, prev := range stack {
+		if prev == filepath.ToSlash(filepath.Clean(name)) {
+			return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), name)
+		}
+	}
+
+	file, data, err := i.readFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to include file %s: %w", file, err)
+	}
+
+	v, err := decodeInclude(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to include file %s: %w", file, err)
+	}
+
+	return i.inlineIncludes(v, append(stack[:len(stack):len(stack)], filepath.ToSlash(filepath.Clean(name))))
+}
+
+// readFile reads name from the first of the dirs which has it, returning the
+// path of the file which was read (or the first attempt when none have it).
+func (i includer) readFile(name string) (string, []byte, error) {
+	var first error
+	for _, dir := range i.dirs {
+		file := filepath.Join(dir, name)
+
+		data, err := readFS(i.fsys, file)
+		if err == nil {
+			return file, data, nil
+		} else if !os.IsNotExist(err) {
+			return file, nil, err
+		} else if first == nil {
+			first = err
+		}
+	}
+
+	return filepath.Join(i.dirs[0], name), nil, first
+}
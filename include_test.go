@@ -0,0 +1,104 @@
+package got
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInclude(t *testing.T) {
+	type header struct {
+		Title string   `json:"title"`
+		Tags  []string `json:"tags"`
+	}
+
+	type document struct {
+		Header header `json:"header"`
+		Body   string `json:"body"`
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		type test struct {
+			Input document `testdata:"input.json,include"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/include", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, test{Input: document{
+			Header: header{Title: "Welcome", Tags: []string{"a", "b"}},
+			Body:   "hello",
+		}}, actual)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		type test struct {
+			Input map[string]any `testdata:"input.json"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/include", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, map[string]any{"$include": "shared/header.json"}, actual.Input["header"])
+	})
+
+	t.Run("other layers", func(t *testing.T) {
+		type test struct {
+			Input document `testdata:"input.json,include"`
+		}
+
+		var mt mockT
+		var actual test
+		LoadDirs(&mt, []string{"testdata/include-layers/shared", "testdata/include-layers/case"}, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, test{Input: document{
+			Header: header{Title: "Shared", Tags: []string{"a"}},
+			Body:   "hello",
+		}}, actual)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		type test struct {
+			Input document `testdata:"input.json,include"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/include-layers/case", new(test))
+
+		require.True(t, mt.failed)
+		require.EqualValues(t, []string{
+			`[GoT] Load: *got.test.Input: file "testdata/include-layers/case/input.json" include error: failed to include file testdata/include-layers/case/header.json: open testdata/include-layers/case/header.json: no such file or directory`,
+		}, mt.logs)
+	})
+
+	t.Run("non-JSON codec", func(t *testing.T) {
+		type test struct {
+			Input map[string]any `testdata:"input.yaml,include"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/yaml", new(test))
+
+		require.EqualValues(t, []string{
+			`[GoT] Load: *got.test.Input: include requires the JSON codec, but got YAML`,
+		}, mt.logs)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		type test struct {
+			Input map[string]any `testdata:"cycle.json,include"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/include", new(test))
+
+		require.EqualValues(t, []string{
+			`[GoT] Load: *got.test.Input: file "testdata/include/cycle.json" include error: include cycle: cycle.json -> shared/cycle-b.json -> cycle.json`,
+		}, mt.logs)
+	})
+}
@@ -3,9 +3,34 @@ package got
 type logger struct {
 	t      tester
 	prefix string
+
+	// buffer collects the lines instead of logging them when set, see
+	// buffered
+	buffer *logBuffer
 }
 
 func (log *logger) Log(msg string, args ...any) {
+	log.log(false, msg, args...)
+}
+
+// Missing logs that a file was not found, which is only logged by a buffered
+// logger when no other files were loaded.
+func (log *logger) Missing(msg string, args ...any) {
+	log.log(true, msg, args...)
+}
+
+func (log *logger) log(missing bool, msg string, args ...any) {
+	if log.buffer != nil {
+		log.buffer.lines = append(log.buffer.lines, logLine{
+			prefix:  log.prefix,
+			msg:     msg,
+			args:    args,
+			missing: missing,
+			input:   log.buffer.input,
+		})
+		return
+	}
+
 	log.t.Logf(log.prefix+": "+msg, args...)
 }
 
@@ -13,5 +38,60 @@ func (log *logger) WithPrefix(prefix string) *logger {
 	return &logger{
 		t:      log.t,
 		prefix: log.prefix + prefix,
+		buffer: log.buffer,
+	}
+}
+
+// logBuffer holds the lines logged while loading a field from multiple input
+// dirs, where input is the index of the dir currently being loaded.
+type logBuffer struct {
+	lines []logLine
+	input int
+}
+
+type logLine struct {
+	prefix  string
+	msg     string
+	args    []any
+	missing bool
+	input   int
+}
+
+// buffered returns a logger which collects lines until flush is called.
+func (log *logger) buffered() *logger {
+	return &logger{
+		t:      log.t,
+		prefix: log.prefix,
+		buffer: new(logBuffer),
+	}
+}
+
+// flush logs the lines for the last input each prefix (eg: an explode key) was
+// loaded from, omitting the files which were not found along with the lines
+// for any earlier inputs which were overridden. When nothing was loaded at
+// all, only the last line is logged.
+func (log *logger) flush() {
+	lines := log.buffer.lines
+	log.buffer = nil
+
+	last := make(map[string]int)
+	for _, line := range lines {
+		if !line.missing {
+			last[line.prefix] = line.input
+		}
+	}
+
+	if len(last) == 0 {
+		if len(lines) > 0 {
+			line := lines[len(lines)-1]
+			log.t.Logf(line.prefix+": "+line.msg, line.args...)
+		}
+		return
+	}
+
+	for _, line := range lines {
+		if input, ok := last[line.prefix]; ok && !line.missing && line.input == input {
+			log.t.Logf(line.prefix+": "+line.msg, line.args...)
+		}
 	}
 }
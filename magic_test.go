@@ -0,0 +1,78 @@
+package got
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/stretchr/testify/require"
+)
+
+var magicPrefix = []byte("GOTJ")
+
+// magicCodec is JSON with a leading signature, like a binary format header.
+type magicCodec struct{}
+
+func (c *magicCodec) Name() string {
+	return "magic"
+}
+
+func (c *magicCodec) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(nil), magicPrefix...), data...), nil
+}
+
+func (c *magicCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(bytes.TrimPrefix(data, magicPrefix), v)
+}
+
+func TestLoadMagic(t *testing.T) {
+	t.Cleanup(codec.Snapshot())
+	codec.RegisterMagic(magicPrefix, new(magicCodec))
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("detected", func(t *testing.T) {
+		type test struct {
+			Input user `testdata:"input.dat"`
+		}
+
+		testLoadOne(t, "magic", new(test), &test{Input: user{Name: "magic"}}, []string{
+			`[GoT] Load: *got.test.Input: detected file "testdata/magic/input.dat" as magic by magic bytes`,
+			`[GoT] Load: *got.test.Input: loaded file "testdata/magic/input.dat" as magic (size 20)`,
+		})
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.dat"`
+		}
+
+		testLoadOne(t, "magic", new(test), &test{Input: `GOTJ{"name":"magic"}`}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/magic/input.dat" as string (size 20)`,
+		})
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		type test struct {
+			Input user `testdata:"unknown.dat"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/magic", new(test))
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				`[GoT] Load: *got.test.Input: failed to get codec for file extension ".dat"`,
+			},
+		}, mt)
+	})
+}
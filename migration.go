@@ -0,0 +1,104 @@
+package got
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// AssertMigration validates moving a fixture from oldFile to newFile, which use
+// different codecs (eg: "input.yml" to "input.json"), before oldFile is
+// deleted. A new value of the same type as proto (or the type it points to) is
+// loaded from oldFile and then saved to newFile when updating golden files.
+//
+// Otherwise, newFile must contain exactly the bytes that saving the value from
+// oldFile would produce, and must decode back into the same value, so the
+// migration neither loses data nor drifts from how Assert would save it.
+func AssertMigration(t tester, oldFile, newFile string, proto any) {
+	t.Helper()
+
+	log := &logger{
+		t:      t,
+		prefix: "[GoT] AssertMigration: ",
+	}
+
+	if err := (Options{}).assertMigration(log, oldFile, newFile, proto); err != nil {
+		t.Fatalf("[GoT] AssertMigration: %s", err.Error())
+	}
+}
+
+func (o Options) assertMigration(log *logger, oldFile, newFile string, proto any) error {
+	if proto == nil {
+		return errors.New("proto cannot be nil")
+	}
+
+	if updateGolden && os.Getenv(lockEnv) != "" {
+		return fmt.Errorf("golden files cannot be updated while %s is set", lockEnv)
+	}
+
+	typ := reflect.TypeOf(proto)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if _, err := os.Stat(oldFile); err != nil {
+		return fmt.Errorf("failed to read old file: %w", err)
+	}
+
+	old := reflect.New(typ)
+	if err := o.loadFile(log, oldFile, fileOptions{}, old.Elem()); err != nil {
+		return err
+	}
+
+	if updateGolden {
+		if err := o.saveFile(log, newFile, reflect.StructField{}, old.Elem()); err != nil {
+			return err
+		}
+	} else {
+		tmp, err := os.MkdirTemp("", "got-migration-")
+		if err != nil {
+			return fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+
+		// the value is saved alongside, so the bytes can be compared exactly
+		file := filepath.Join(tmp, filepath.Base(newFile))
+		if err := o.saveFile(log, file, reflect.StructField{}, old.Elem()); err != nil {
+			return err
+		}
+
+		want, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", file, err)
+		}
+
+		got, err := os.ReadFile(newFile)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("new file %q not found, run with -update-golden to create it", newFile)
+		} else if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", newFile, err)
+		}
+
+		if !bytes.Equal(got, want) {
+			return fmt.Errorf("new file %q does not match the migrated contents of %q: %s", newFile, oldFile, cmp.Diff(string(want), string(got)))
+		}
+	}
+
+	migrated := reflect.New(typ)
+	if err := o.loadFile(log, newFile, fileOptions{}, migrated.Elem()); err != nil {
+		return err
+	}
+
+	opts := compareOptions(old.Interface())
+
+	if !cmp.Equal(old.Interface(), migrated.Interface(), opts...) {
+		return fmt.Errorf("migration of %q to %q is not stable: %s", oldFile, newFile, cmp.Diff(old.Interface(), migrated.Interface(), opts...))
+	}
+
+	return nil
+}
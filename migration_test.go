@@ -0,0 +1,88 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertMigration(t *testing.T) {
+	type user struct {
+		Name string   `json:"name" yaml:"name"`
+		Tags []string `json:"tags" yaml:"tags"`
+	}
+
+	t.Run("update", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "user.json")
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		AssertMigration(&mt, "testdata/migration/user.yaml", file, user{})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, "{\n  \"name\": \"alice\",\n  \"tags\": [\n    \"admin\",\n    \"dev\"\n  ]\n}", string(data))
+	})
+
+	t.Run("matching", func(t *testing.T) {
+		var mt mockT
+		AssertMigration(&mt, "testdata/migration/user.yaml", "testdata/migration/user.json", new(user))
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("different bytes", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "user.json")
+		require.NoError(t, os.WriteFile(file, []byte(`{"name":"alice","tags":["admin","dev"]}`), 0644))
+
+		var mt mockT
+		AssertMigration(&mt, "testdata/migration/user.yaml", file, user{})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], `[GoT] AssertMigration: new file "`+file+`" does not match the migrated contents of "testdata/migration/user.yaml"`)
+	})
+
+	t.Run("missing new file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "user.json")
+
+		var mt mockT
+		AssertMigration(&mt, "testdata/migration/user.yaml", file, user{})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], `new file "`+file+`" not found, run with -update-golden to create it`)
+	})
+
+	t.Run("missing old file", func(t *testing.T) {
+		var mt mockT
+		AssertMigration(&mt, "testdata/migration/missing.yaml", "testdata/migration/user.json", user{})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "failed to read old file")
+	})
+
+	t.Run("lossy", func(t *testing.T) {
+		registerLossyCodec(t)
+
+		file := filepath.Join(t.TempDir(), "user.lossy")
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		AssertMigration(&mt, "testdata/migration/user.yaml", file, user{})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], `migration of "testdata/migration/user.yaml" to "`+file+`" is not stable`)
+	})
+
+	t.Run("nil proto", func(t *testing.T) {
+		var mt mockT
+		AssertMigration(&mt, "testdata/migration/user.yaml", "testdata/migration/user.json", nil)
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "proto cannot be nil")
+	})
+}
@@ -0,0 +1,30 @@
+package got
+
+var normalizers = newTransformers()
+
+// RegisterNormalizer adds a normalizer for files with the extension ext, which
+// removes (or rewrites) volatile details such as a timestamp line in a ".log"
+// file. Unlike a formatter, a normalizer may discard information, so it is
+// only applied by Assert: to both the golden file and the actual value of raw
+// (string and []byte) fields before comparing, and to the contents of golden
+// files when they are saved. Values loaded by Load are left untouched.
+//
+// The returned func restores the normalizer that was previously registered
+// for ext (if any), such as `t.Cleanup(got.RegisterNormalizer(".log", fn))`.
+func RegisterNormalizer(ext string, fn func([]byte) ([]byte, error)) func() {
+	return normalizers.register(ext, fn)
+}
+
+func normalizeData(file string, data []byte) ([]byte, error) {
+	return normalizers.apply(file, data)
+}
+
+// normalizeValues returns a shallow copy of input (a pointer to a struct) where
+// the raw fields with a registered normalizer have been normalized.
+func normalizeValues(input any) (any, error) {
+	if normalizers.empty() {
+		return input, nil
+	}
+
+	return transformValues(input, "normalize", normalizeData)
+}
@@ -0,0 +1,78 @@
+package got
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterNormalizer(t *testing.T) {
+	timestamp := regexp.MustCompile(`(?m)^time: .*\n`)
+
+	t.Cleanup(RegisterNormalizer(".log", func(data []byte) ([]byte, error) {
+		return timestamp.ReplaceAll(data, nil), nil
+	}))
+	t.Cleanup(RegisterNormalizer(".invalid", func(data []byte) ([]byte, error) {
+		return nil, errors.New("invalid syntax")
+	}))
+
+	type test struct {
+		Output string `testdata:"output.log"`
+	}
+
+	t.Run("compare", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "output.log"), []byte("time: 2024-01-01T00:00:00Z\nstarted\n"), 0644))
+
+		var mt mockT
+		Assert(&mt, dir, &test{Output: "time: 2025-06-30T12:34:56Z\nstarted\n"})
+		require.False(t, mt.failed, mt.logs)
+
+		mt = mockT{}
+		Assert(&mt, dir, &test{Output: "time: 2025-06-30T12:34:56Z\nstopped\n"})
+		require.True(t, mt.failed)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		Assert(&mt, dir, &test{Output: "time: 2025-06-30T12:34:56Z\nstarted\n"})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "output.log"))
+		require.NoError(t, err)
+		require.Equal(t, "started\n", string(data))
+	})
+
+	t.Run("load", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "output.log"), []byte("time: 2024-01-01T00:00:00Z\nstarted\n"), 0644))
+
+		var mt mockT
+		var actual test
+		Load(&mt, dir, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "time: 2024-01-01T00:00:00Z\nstarted\n", actual.Output)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		type test struct {
+			Output string `testdata:"output.invalid"`
+		}
+
+		var mt mockT
+		Assert(&mt, t.TempDir(), &test{Output: "hello"})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], `*got.test.Output: failed to normalize "output.invalid": invalid syntax`)
+	})
+}
@@ -0,0 +1,223 @@
+package got
+
+import (
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"runtime"
+
+	"github.com/dominicbarnes/got/v2/codec"
+)
+
+// Options customizes the behavior of Load, LoadDirs and Assert. The zero value
+// behaves identically to the package-level functions.
+type Options struct {
+	// AllowUnknownCodecs makes fields whose file extension has no registered
+	// codec log a message and be skipped (left as-is) instead of failing the
+	// test. This is useful in the middle of a refactor, but is opt-in so that
+	// typos in file extensions are caught by default.
+	AllowUnknownCodecs bool
+
+	// Reset zeroes each output struct before loading, so values loaded by a
+	// prior call (eg: when reusing a struct across test cases in a loop) do
+	// not linger in fields that are not found this time around. This is opt-in
+	// because it conflicts with pre-seeding values to be merged with fixtures.
+	Reset bool
+
+	// CollectAll makes Assert check every value and report all of the failures
+	// together, rather than stopping at the first value that fails.
+	CollectAll bool
+
+	// MaxFileSize is the largest file (in bytes) that will be read, guarding
+	// against a misconfigured path pointing at a huge file. The default of 0
+	// means there is no limit.
+	MaxFileSize int64
+
+	// WriteDiff makes a failed Assert also write the diff to a file, so it can
+	// be collected as an artifact in CI rather than being buried in the test
+	// output. The file is named after the directory being asserted, so
+	// "testdata/case-1" writes "testdata/case-1.diff" (and removes it again
+	// once the assertion passes). This has no effect when updating golden
+	// files.
+	WriteDiff bool
+
+	// DiffDir is where diff files are written when WriteDiff is enabled,
+	// instead of next to the directory being asserted.
+	DiffDir string
+
+	// WriteActual makes a failed Assert write the actual value for each field
+	// that does not match to a file next to the golden file with an ".actual"
+	// extension (eg: "output.json.actual"), so the two can be compared in an
+	// editor. Those files are removed again once the fields match.
+	WriteActual bool
+
+	// AbsentIsEmpty makes Assert treat a missing golden file as an explicit
+	// expectation that the field is empty, failing with a message which points
+	// at the missing file (rather than a diff against the zero value) when the
+	// actual value is not empty.
+	AbsentIsEmpty bool
+
+	// Review is consulted for each golden file that does not match when
+	// asserting, which can accept the actual value (eg: after printing the diff
+	// and prompting, or based on an environment variable) to update that file
+	// rather than failing. The assertion passes when every mismatch has been
+	// accepted. The default of nil never updates, and reviews are skipped while
+	// GOT_NO_UPDATE is set.
+	Review ReviewFunc
+
+	// CodecResolver chooses the codec for a file (eg: one without an extension)
+	// given the path and struct field it is loaded into or saved from. It is
+	// consulted before the codec registered for the file extension, which is
+	// used instead when it returns false.
+	CodecResolver func(file string, field reflect.StructField) (codec.Codec, bool)
+
+	// RelativeToCaller resolves relative directories against the directory of
+	// the source file calling Load, LoadDirs or Assert, rather than the working
+	// directory. The working directory is the package directory when run by
+	// "go test", but this keeps paths stable when invoked by other tools.
+	RelativeToCaller bool
+
+	// StrictLoad makes Load and LoadDirs reject fields in input files which
+	// are not defined by the struct being decoded into, for codecs which
+	// implement codec.Stricter (eg: JSON). Assert remains lenient when loading
+	// golden files, so those can be trimmed by hand without failing.
+	StrictLoad bool
+
+	// CacheFiles reuses the contents of files which have already been read
+	// (by any call with CacheFiles enabled) while their modification time and
+	// size are unchanged, which speeds up suites where many test cases load the
+	// same large fixture from a SharedDir (eg: via TestCase.LoadDirsList). Each
+	// call still decodes its own copy, so test cases cannot affect each other.
+	CacheFiles bool
+
+	// Timing logs how long it took to open, read and decode each file after it
+	// has been loaded, which helps to find the fixtures that dominate the time
+	// taken by a slow suite (eg: a huge YAML file that is slow to parse).
+	Timing bool
+
+	// HTTPClient is used to fetch fields whose struct tag is an http:// or
+	// https:// URL (eg: `testdata:"https://schemas.example.com/user.json"`).
+	// Fetching is opt-in since it requires network access, so those fields
+	// fail to load while this is nil. An httptest.Server can provide a client
+	// for tests.
+	HTTPClient *http.Client
+
+	// QuietLayers logs a single line for each field when loading from multiple
+	// directories (eg: LoadDirs, or TestCase.Load with a SharedDir), which is
+	// the file it was finally loaded from, rather than a line for every
+	// directory including those where the file was not found.
+	QuietLayers bool
+
+	// SkipUpdate lists the names of fields (eg: "Blob") which are left as-is
+	// when updating golden files, while every other field is saved as usual.
+	// Unlike the "frozen" option, this only applies to a single call (eg: for
+	// a targeted regeneration) and those fields are not compared either.
+	SkipUpdate []string
+
+	// strict is set by Load and LoadDirs when StrictLoad is enabled, so that
+	// it does not apply when Assert loads golden files
+	strict bool
+
+	// config holds the defaults from the configFile of the directory being
+	// loaded from or saved to
+	config dirConfig
+
+	// only restricts loading to the named fields, as used by LoadOnly
+	only []string
+
+	// saveFrozen saves fields using the "frozen" option too, as used by
+	// AssertRoundTrip since it only saves to a temp dir
+	saveFrozen bool
+
+	// plan collects the files which would be saved instead of writing them,
+	// as used by PlanSave
+	plan *savePlan
+
+	// fsys is the source of the files being loaded when it is not the OS, as
+	// used by LoadTar and LoadGitRef
+	fsys fileSystem
+
+	// inputs are all the directories being loaded from, which the "include"
+	// option reads files from
+	inputs []string
+}
+
+// Load is the same as the package-level Load, but configured by o.
+func (o Options) Load(t tester, dir string, values ...any) {
+	t.Helper()
+
+	log := &logger{
+		t:      t,
+		prefix: "[GoT] Load: ",
+	}
+
+	o.strict = o.StrictLoad
+
+	if err := o.loadDirs(log, o.resolveDirs(dir), values...); err != nil {
+		t.Fatalf("[GoT] Load: %s", err.Error())
+	}
+}
+
+// LoadDirs is the same as the package-level LoadDirs, but configured by o.
+func (o Options) LoadDirs(t tester, dirs []string, values ...any) {
+	t.Helper()
+
+	log := &logger{
+		t:      t,
+		prefix: "[GoT] Load: ",
+	}
+
+	o.strict = o.StrictLoad
+
+	if err := o.loadDirs(log, o.resolveDirs(dirs...), values...); err != nil {
+		t.Fatalf("[GoT] LoadDirs: %s", err.Error())
+	}
+}
+
+// Assert is the same as the package-level Assert, but configured by o.
+func (o Options) Assert(t tester, dir string, values ...any) {
+	t.Helper()
+
+	log := &logger{
+		t:      t,
+		prefix: "[GoT] Assert: ",
+	}
+
+	if err := o.assert(log, o.resolveDirs(dir)[0], values...); err != nil {
+		t.Fatalf("[GoT] Assert: %s", err.Error())
+	}
+}
+
+// resolveDirs returns dirs within the root configured by SetTestDataRoot, and
+// then relative to the source file of the caller of the exported method when
+// RelativeToCaller is set, leaving absolute dirs as-is.
+func (o Options) resolveDirs(dirs ...string) []string {
+	if testDataRoot != "" {
+		rooted := make([]string, len(dirs))
+		for i, dir := range dirs {
+			rooted[i] = withTestDataRoot(dir)
+		}
+		dirs = rooted
+	}
+
+	if !o.RelativeToCaller {
+		return dirs
+	}
+
+	// skip this function and the exported method to reach the caller
+	_, file, _, ok := runtime.Caller(2)
+	if !ok {
+		return dirs
+	}
+
+	resolved := make([]string, len(dirs))
+	for i, dir := range dirs {
+		if filepath.IsAbs(dir) {
+			resolved[i] = dir
+		} else {
+			resolved[i] = filepath.Join(filepath.Dir(file), dir)
+		}
+	}
+
+	return resolved
+}
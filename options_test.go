@@ -0,0 +1,569 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions(t *testing.T) {
+	t.Run("allow unknown codecs", func(t *testing.T) {
+		type test struct {
+			Unknown struct{ Hello string } `testdata:"input.unknown"`
+			Valid   string                 `testdata:"input.txt"`
+		}
+
+		t.Run("strict", func(t *testing.T) {
+			var mt mockT
+			var actual test
+			Options{}.Load(&mt, "testdata/unknown", &actual)
+
+			require.EqualValues(t, mockT{
+				helper: true,
+				failed: true,
+				logs: []string{
+					`[GoT] Load: *got.test.Unknown: failed to get codec for file extension ".unknown"`,
+				},
+			}, mt)
+		})
+
+		t.Run("lenient", func(t *testing.T) {
+			var mt mockT
+			var actual test
+			Options{AllowUnknownCodecs: true}.Load(&mt, "testdata/unknown", &actual)
+
+			require.EqualValues(t, test{Valid: "hello world"}, actual)
+
+			require.EqualValues(t, mockT{
+				helper: true,
+				logs: []string{
+					`[GoT] Load: *got.test.Unknown: skipped: file "testdata/unknown/input.unknown" has no registered codec`,
+					`[GoT] Load: *got.test.Valid: loaded file "testdata/unknown/input.txt" as string (size 11)`,
+				},
+			}, mt)
+		})
+	})
+	t.Run("reset", func(t *testing.T) {
+		type test struct {
+			A string `testdata:"a.txt"`
+			B string `testdata:"b.txt"`
+		}
+
+		t.Run("disabled", func(t *testing.T) {
+			var mt mockT
+			var actual test
+
+			Options{}.Load(&mt, "testdata/multiple", &actual)
+			Options{}.Load(&mt, "testdata/multiple-dirs/dir1", &actual)
+
+			require.False(t, mt.failed, mt.logs)
+			require.EqualValues(t, test{A: "A", B: "B"}, actual)
+		})
+
+		t.Run("enabled", func(t *testing.T) {
+			var mt mockT
+			var actual test
+
+			Options{Reset: true}.Load(&mt, "testdata/multiple", &actual)
+			Options{Reset: true}.Load(&mt, "testdata/multiple-dirs/dir1", &actual)
+
+			require.False(t, mt.failed, mt.logs)
+			require.EqualValues(t, test{A: "A"}, actual)
+		})
+	})
+
+	t.Run("collect all", func(t *testing.T) {
+		type a struct {
+			A string `testdata:"a.txt"`
+		}
+
+		type b struct {
+			B string `testdata:"b.txt"`
+		}
+
+		t.Run("disabled", func(t *testing.T) {
+			var mt mockT
+			Options{}.Assert(&mt, "testdata/multiple", &a{A: "X"}, &b{B: "Y"})
+
+			require.True(t, mt.failed)
+			require.Len(t, mt.logs, 2)
+			require.True(t, strings.HasPrefix(mt.logs[1], "[GoT] Assert: test of *got.a failed:"), mt.logs[1])
+			require.NotContains(t, mt.logs[1], "*got.b")
+		})
+
+		t.Run("enabled", func(t *testing.T) {
+			var mt mockT
+			Options{CollectAll: true}.Assert(&mt, "testdata/multiple", &a{A: "X"}, &b{B: "Y"})
+
+			require.True(t, mt.failed)
+			require.Len(t, mt.logs, 3)
+			require.True(t, strings.HasPrefix(mt.logs[2], "[GoT] Assert: test of *got.a failed:"), mt.logs[2])
+			require.Contains(t, mt.logs[2], "\ntest of *got.b failed:")
+		})
+	})
+
+	t.Run("max file size", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.txt"`
+		}
+
+		t.Run("within limit", func(t *testing.T) {
+			var mt mockT
+			var actual test
+			Options{MaxFileSize: 11}.Load(&mt, "testdata/text", &actual)
+
+			require.False(t, mt.failed, mt.logs)
+			require.EqualValues(t, test{Input: "hello world"}, actual)
+		})
+
+		t.Run("exceeds limit", func(t *testing.T) {
+			var mt mockT
+			Options{MaxFileSize: 10}.Load(&mt, "testdata/text", new(test))
+
+			require.EqualValues(t, mockT{
+				helper: true,
+				failed: true,
+				logs: []string{
+					`[GoT] Load: *got.test.Input: file "testdata/text/input.txt" exceeds max file size of 10 bytes`,
+				},
+			}, mt)
+		})
+	})
+
+	t.Run("write diff", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.txt"`
+		}
+
+		setup := func(t *testing.T) string {
+			dir := filepath.Join(t.TempDir(), "case-1")
+			require.NoError(t, os.Mkdir(dir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "input.txt"), []byte("hello world"), 0644))
+			return dir
+		}
+
+		t.Run("failure", func(t *testing.T) {
+			dir := setup(t)
+
+			var mt mockT
+			Options{WriteDiff: true}.Assert(&mt, dir, &test{Input: "foo bar"})
+			require.True(t, mt.failed)
+
+			diff, err := os.ReadFile(dir + ".diff")
+			require.NoError(t, err)
+			require.Contains(t, string(diff), `"hello world"`)
+			require.Contains(t, string(diff), `"foo bar"`)
+		})
+
+		t.Run("success", func(t *testing.T) {
+			dir := setup(t)
+			require.NoError(t, os.WriteFile(dir+".diff", []byte("stale"), 0644))
+
+			var mt mockT
+			Options{WriteDiff: true}.Assert(&mt, dir, &test{Input: "hello world"})
+			require.False(t, mt.failed, mt.logs)
+
+			_, err := os.Stat(dir + ".diff")
+			require.True(t, os.IsNotExist(err))
+		})
+
+		t.Run("diff dir", func(t *testing.T) {
+			dir := setup(t)
+			artifacts := filepath.Join(t.TempDir(), "artifacts")
+
+			var mt mockT
+			Options{WriteDiff: true, DiffDir: artifacts}.Assert(&mt, dir, &test{Input: "foo bar"})
+			require.True(t, mt.failed)
+
+			_, err := os.Stat(filepath.Join(artifacts, "case-1.diff"))
+			require.NoError(t, err)
+
+			_, err = os.Stat(dir + ".diff")
+			require.True(t, os.IsNotExist(err))
+		})
+
+		t.Run("update golden", func(t *testing.T) {
+			updateGolden = true
+			t.Cleanup(func() { updateGolden = false })
+
+			dir := setup(t)
+
+			var mt mockT
+			Options{WriteDiff: true}.Assert(&mt, dir, &test{Input: "foo bar"})
+			require.False(t, mt.failed, mt.logs)
+
+			_, err := os.Stat(dir + ".diff")
+			require.True(t, os.IsNotExist(err))
+		})
+	})
+
+	t.Run("write actual", func(t *testing.T) {
+		type test struct {
+			Input  string            `testdata:"input.txt"`
+			Output map[string]string `testdata:"output.json"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "input.txt"), []byte("hello world"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "output.json"), []byte(`{"a": "b"}`), 0644))
+
+		t.Run("failure", func(t *testing.T) {
+			var mt mockT
+			Options{WriteActual: true}.Assert(&mt, dir, &test{
+				Input:  "foo bar",
+				Output: map[string]string{"a": "b"},
+			})
+
+			require.True(t, mt.failed)
+			require.Equal(t, `[GoT] Assert: *got.test.Input: saved actual file "`+filepath.Join(dir, "input.txt.actual")+`" (size 7)`, mt.logs[2])
+
+			data, err := os.ReadFile(filepath.Join(dir, "input.txt.actual"))
+			require.NoError(t, err)
+			require.Equal(t, "foo bar", string(data))
+
+			_, err = os.Stat(filepath.Join(dir, "output.json.actual"))
+			require.True(t, os.IsNotExist(err))
+		})
+
+		t.Run("success", func(t *testing.T) {
+			var mt mockT
+			Options{WriteActual: true}.Assert(&mt, dir, &test{
+				Input:  "hello world",
+				Output: map[string]string{"a": "b"},
+			})
+
+			require.False(t, mt.failed, mt.logs)
+
+			_, err := os.Stat(filepath.Join(dir, "input.txt.actual"))
+			require.True(t, os.IsNotExist(err))
+		})
+
+		t.Run("manifest", func(t *testing.T) {
+			type test struct {
+				Input    string            `testdata:"input.txt"`
+				Expected map[string]string `testdata:"expected/*.txt,explode,strip=expected"`
+			}
+
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, manifestFile), []byte(`{"Input": "renamed.txt"}`), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "renamed.txt"), []byte("hello world"), 0644))
+
+			var mt mockT
+			Options{WriteActual: true}.Assert(&mt, dir, &test{
+				Input:    "foo bar",
+				Expected: map[string]string{"a.txt": "A"},
+			})
+			require.True(t, mt.failed)
+
+			data, err := os.ReadFile(filepath.Join(dir, "renamed.txt.actual"))
+			require.NoError(t, err)
+			require.Equal(t, "foo bar", string(data))
+
+			data, err = os.ReadFile(filepath.Join(dir, "expected", "a.txt.actual"))
+			require.NoError(t, err)
+			require.Equal(t, "A", string(data))
+		})
+	})
+
+	t.Run("relative to caller", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.txt"`
+		}
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(t.TempDir()))
+		t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+		t.Run("disabled", func(t *testing.T) {
+			var mt mockT
+			var actual test
+			Options{}.Load(&mt, "testdata/text", &actual)
+
+			require.Empty(t, actual.Input)
+		})
+
+		t.Run("load", func(t *testing.T) {
+			var mt mockT
+			var actual test
+			Options{RelativeToCaller: true}.Load(&mt, "testdata/text", &actual)
+
+			require.False(t, mt.failed, mt.logs)
+			require.Equal(t, test{Input: "hello world"}, actual)
+			require.EqualValues(t, []string{
+				`[GoT] Load: *got.test.Input: loaded file "` + filepath.Join(wd, "testdata/text/input.txt") + `" as string (size 11)`,
+			}, mt.logs)
+		})
+
+		t.Run("assert", func(t *testing.T) {
+			var mt mockT
+			Options{RelativeToCaller: true}.Assert(&mt, "testdata/text", &test{Input: "hello world"})
+
+			require.False(t, mt.failed, mt.logs)
+		})
+	})
+
+	t.Run("codec resolver", func(t *testing.T) {
+		type user struct {
+			Name string `json:"name"`
+		}
+
+		type test struct {
+			Input user `testdata:"input"`
+		}
+
+		resolver := func(file string, field reflect.StructField) (codec.Codec, bool) {
+			if field.Name != "Input" {
+				return nil, false
+			}
+			return &codec.JSONCodec{Indent: "  "}, true
+		}
+
+		t.Run("default", func(t *testing.T) {
+			var mt mockT
+			Options{}.Load(&mt, "testdata/resolver", new(test))
+
+			require.EqualValues(t, []string{
+				`[GoT] Load: *got.test.Input: failed to get codec for file extension ""`,
+			}, mt.logs)
+		})
+
+		t.Run("load", func(t *testing.T) {
+			var mt mockT
+			var actual test
+			Options{CodecResolver: resolver}.Load(&mt, "testdata/resolver", &actual)
+
+			require.False(t, mt.failed, mt.logs)
+			require.Equal(t, test{Input: user{Name: "alice"}}, actual)
+		})
+
+		t.Run("save", func(t *testing.T) {
+			updateGolden = true
+			t.Cleanup(func() { updateGolden = false })
+
+			dir := t.TempDir()
+
+			var mt mockT
+			Options{CodecResolver: resolver}.Assert(&mt, dir, &test{Input: user{Name: "alice"}})
+			require.False(t, mt.failed, mt.logs)
+
+			actual, err := os.ReadFile(filepath.Join(dir, "input"))
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile("testdata/resolver/input")
+			require.NoError(t, err)
+
+			require.Equal(t, string(expected), string(actual))
+		})
+	})
+
+	t.Run("strict load", func(t *testing.T) {
+		type testObject struct {
+			Name string `json:"name"`
+		}
+
+		type test struct {
+			Input  testObject `testdata:"input.json"`
+			Output testObject `testdata:"output.json"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "input.json"), []byte(`{"name":"input","extra":true}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "output.json"), []byte(`{"name":"output","extra":true}`), 0644))
+
+		t.Run("disabled", func(t *testing.T) {
+			var mt mockT
+			var actual test
+			Options{}.Load(&mt, dir, &actual)
+
+			require.False(t, mt.failed, mt.logs)
+			require.Equal(t, "input", actual.Input.Name)
+		})
+
+		t.Run("load", func(t *testing.T) {
+			var mt mockT
+			Options{StrictLoad: true}.Load(&mt, dir, new(test))
+
+			require.True(t, mt.failed)
+			require.Contains(t, mt.logs[len(mt.logs)-1], `[GoT] Load: *got.test.Input: file "`+filepath.Join(dir, "input.json")+`" decode error: json: unknown field "extra"`)
+		})
+
+		t.Run("load dirs", func(t *testing.T) {
+			var mt mockT
+			Options{StrictLoad: true}.LoadDirs(&mt, []string{dir}, new(test))
+
+			require.True(t, mt.failed)
+		})
+
+		t.Run("assert", func(t *testing.T) {
+			var mt mockT
+			Options{StrictLoad: true}.Assert(&mt, dir, &test{Input: testObject{Name: "input"}, Output: testObject{Name: "output"}})
+
+			require.False(t, mt.failed, mt.logs)
+		})
+
+		t.Run("registered codec unchanged", func(t *testing.T) {
+			c, err := codec.Get(".json")
+			require.NoError(t, err)
+			require.False(t, c.(*codec.JSONCodec).DisallowUnknownFields)
+		})
+	})
+
+	t.Run("timing", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.txt"`
+		}
+
+		pattern := regexp.MustCompile(`^\[GoT\] Load: \*got\.test\.Input: timing for file "testdata/text/input\.txt": open \S+, read \S+, decode \S+$`)
+
+		t.Run("disabled", func(t *testing.T) {
+			var mt mockT
+			Options{}.Load(&mt, "testdata/text", new(test))
+
+			require.False(t, mt.failed, mt.logs)
+			for _, line := range mt.logs {
+				require.NotContains(t, line, "timing for file")
+			}
+		})
+
+		t.Run("enabled", func(t *testing.T) {
+			var mt mockT
+			Options{Timing: true}.Load(&mt, "testdata/text", new(test))
+
+			require.False(t, mt.failed, mt.logs)
+			require.Len(t, mt.logs, 2)
+			require.Regexp(t, pattern, mt.logs[1])
+		})
+
+		t.Run("cached", func(t *testing.T) {
+			var mt mockT
+			Options{Timing: true, CacheFiles: true}.Load(&mt, "testdata/text", new(test))
+
+			require.False(t, mt.failed, mt.logs)
+			require.Regexp(t, pattern, mt.logs[len(mt.logs)-1])
+		})
+	})
+
+	t.Run("absent is empty", func(t *testing.T) {
+		type test struct {
+			Input  string `testdata:"input.txt"`
+			Output string `testdata:"output.txt"`
+		}
+
+		t.Run("non-empty actual", func(t *testing.T) {
+			var mt mockT
+			Options{AbsentIsEmpty: true}.Assert(&mt, "testdata/text", &test{Input: "hello world", Output: "unexpected"})
+
+			require.EqualValues(t, mockT{
+				helper: true,
+				failed: true,
+				logs: []string{
+					`[GoT] Assert: *got.test.Output: golden file "testdata/text/output.txt" does not exist, so the value is expected to be empty (use -update-golden to create it)`,
+				},
+			}, mt)
+		})
+
+		t.Run("empty actual", func(t *testing.T) {
+			var mt mockT
+			Options{AbsentIsEmpty: true}.Assert(&mt, "testdata/text", &test{Input: "hello world"})
+
+			require.False(t, mt.failed, mt.logs)
+		})
+	})
+
+	t.Run("skip update", func(t *testing.T) {
+		type test struct {
+			Output string `testdata:"output.txt"`
+			Blob   string `testdata:"blob.txt"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "output.txt"), []byte("old output"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "blob.txt"), []byte("old blob"), 0644))
+
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+
+		t.Run("excluded", func(t *testing.T) {
+			var mt mockT
+			Options{SkipUpdate: []string{"Blob"}}.Assert(&mt, dir, &test{Output: "new output", Blob: "new blob"})
+			require.False(t, mt.failed, mt.logs)
+			require.Contains(t, mt.logs, "[GoT] Assert: *got.test.Blob: skipped: field is excluded by SkipUpdate")
+
+			data, err := os.ReadFile(filepath.Join(dir, "output.txt"))
+			require.NoError(t, err)
+			require.Equal(t, "new output", string(data))
+
+			data, err = os.ReadFile(filepath.Join(dir, "blob.txt"))
+			require.NoError(t, err)
+			require.Equal(t, "old blob", string(data))
+		})
+
+		t.Run("unknown field", func(t *testing.T) {
+			var mt mockT
+			Options{SkipUpdate: []string{"Blbo"}}.Assert(&mt, dir, &test{Output: "new output", Blob: "new blob"})
+
+			require.True(t, mt.failed)
+			require.Contains(t, mt.logs[len(mt.logs)-1], "[GoT] Assert: SkipUpdate references unknown field Blbo")
+		})
+	})
+
+	t.Run("quiet layers", func(t *testing.T) {
+		dirs := []string{"testdata/multiple-dirs/dir1", "testdata/multiple-dirs/dir2", "testdata/unknown"}
+
+		t.Run("files", func(t *testing.T) {
+			type test struct {
+				A string `testdata:"a.txt"`
+				B string `testdata:"b.txt"`
+				C string `testdata:"c.txt"`
+			}
+
+			var mt mockT
+			var actual test
+			Options{QuietLayers: true}.LoadDirs(&mt, dirs, &actual)
+
+			require.EqualValues(t, test{A: "A", B: "B"}, actual)
+			require.EqualValues(t, []string{
+				`[GoT] Load: *got.test.A: loaded file "testdata/multiple-dirs/dir1/a.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.B: loaded file "testdata/multiple-dirs/dir2/b.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.C: skipped: file "testdata/unknown/c.txt" not found`,
+			}, mt.logs)
+		})
+
+		t.Run("explode", func(t *testing.T) {
+			type test struct {
+				Files map[string]string `testdata:"expected/*.txt,explode"`
+			}
+
+			var mt mockT
+			var actual test
+			Options{QuietLayers: true}.LoadDirs(&mt, []string{"testdata/multiple-dirs-explode/dir1", "testdata/multiple-dirs-explode/dir2", "testdata/unknown"}, &actual)
+
+			require.Len(t, actual.Files, 3)
+			require.EqualValues(t, []string{
+				`[GoT] Load: *got.test.Files["expected/a.txt"]: loaded file "testdata/multiple-dirs-explode/dir1/expected/a.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Files["expected/b.txt"]: loaded file "testdata/multiple-dirs-explode/dir2/expected/b.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Files["expected/c.txt"]: loaded file "testdata/multiple-dirs-explode/dir2/expected/c.txt" as string (size 1)`,
+			}, mt.logs)
+		})
+
+		t.Run("single dir", func(t *testing.T) {
+			type test struct {
+				B string `testdata:"b.txt"`
+			}
+
+			var mt mockT
+			Options{QuietLayers: true}.Load(&mt, "testdata/multiple-dirs/dir1", new(test))
+
+			require.EqualValues(t, []string{
+				`[GoT] Load: *got.test.B: skipped: file "testdata/multiple-dirs/dir1/b.txt" not found`,
+			}, mt.logs)
+		})
+	})
+}
@@ -0,0 +1,141 @@
+package got
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/fatih/structtag"
+)
+
+// The "resolvepaths" option treats the loaded strings as file paths, where any
+// relative paths are resolved against the input directory to get an absolute
+// path (eg: `testdata:"config-path.txt,resolvepaths"`). This works for string
+// fields as well as slices and maps of strings.
+//
+// When saving, paths within the directory are made relative to it again, while
+// any other paths are saved as absolute paths.
+const resolvePathsOption = "resolvepaths"
+
+// getResolveDir returns the dir which relative paths are resolved against for
+// a field using the "resolvepaths" option, which is empty when it is not set.
+func getResolveDir(input string, typ reflect.Type, tag *structtag.Tag) (string, error) {
+	if !tag.HasOption(resolvePathsOption) {
+		return "", nil
+	}
+
+	if isMap(typ) && tag.HasOption("explode") {
+		typ = typ.Elem()
+	}
+
+	if !isStringType(typ) {
+		return "", fmt.Errorf("resolvepaths requires string values, but got %s", typ)
+	}
+
+	dir, err := filepath.Abs(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve dir %s: %w", input, err)
+	}
+
+	return dir, nil
+}
+
+// isStringType reports whether typ is a string, or a pointer, slice, array or
+// map containing strings.
+func isStringType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return isStringType(typ.Elem())
+	default:
+		return false
+	}
+}
+
+// resolvePaths joins each relative path within value to dir, trimming any
+// surrounding whitespace (eg: a trailing newline in a raw file).
+func resolvePaths(dir string, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.String:
+		path := strings.TrimSpace(value.String())
+		if path == "" {
+			return
+		} else if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		value.SetString(path)
+	case reflect.Ptr:
+		if !value.IsNil() {
+			resolvePaths(dir, value.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			resolvePaths(dir, value.Index(i))
+		}
+	case reflect.Map:
+		// map values cannot be set directly, so each is copied and set again
+		for _, key := range value.MapKeys() {
+			v := reflect.New(value.Type().Elem()).Elem()
+			v.Set(value.MapIndex(key))
+			resolvePaths(dir, v)
+			value.SetMapIndex(key, v)
+		}
+	}
+}
+
+// relativePaths returns a copy of value where each absolute path within dir is
+// made relative to it again, which is the inverse of resolvePaths for saving.
+func relativePaths(dir string, value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.String:
+		path := value.String()
+		if filepath.IsAbs(path) {
+			rel, err := filepath.Rel(dir, path)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				path = rel
+			}
+		}
+
+		v := reflect.New(value.Type()).Elem()
+		v.SetString(path)
+		return v
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+
+		p := reflect.New(value.Type().Elem())
+		p.Elem().Set(relativePaths(dir, value.Elem()))
+		return p
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		s := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			s.Index(i).Set(relativePaths(dir, value.Index(i)))
+		}
+		return s
+	case reflect.Array:
+		a := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			a.Index(i).Set(relativePaths(dir, value.Index(i)))
+		}
+		return a
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		m := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for _, key := range value.MapKeys() {
+			m.SetMapIndex(key, relativePaths(dir, value.MapIndex(key)))
+		}
+		return m
+	default:
+		return value
+	}
+}
@@ -0,0 +1,112 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePaths(t *testing.T) {
+	dir, err := filepath.Abs("testdata/paths")
+	require.NoError(t, err)
+
+	t.Run("string", func(t *testing.T) {
+		type test struct {
+			Config string `testdata:"config-path.txt,resolvepaths"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/paths", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, filepath.Join(dir, "config/app.json"), actual.Config)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		type test struct {
+			Paths map[string]string `testdata:"paths.json,resolvepaths"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/paths", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, map[string]string{
+			"main":   filepath.Join(dir, "main.json"),
+			"shared": "/etc/shared.json",
+		}, actual.Paths)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		type test struct {
+			Config string `testdata:"config-path.txt"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/paths", &actual)
+
+		require.Equal(t, "config/app.json\n", actual.Config)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		type test struct {
+			Config string            `testdata:"config-path.txt,resolvepaths"`
+			Paths  map[string]string `testdata:"paths.json,resolvepaths"`
+		}
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		tmp := t.TempDir()
+		abs, err := filepath.Abs(tmp)
+		require.NoError(t, err)
+
+		value := &test{
+			Config: filepath.Join(abs, "config/app.json"),
+			Paths: map[string]string{
+				"main":   filepath.Join(abs, "main.json"),
+				"shared": "/etc/shared.json",
+			},
+		}
+
+		var mt mockT
+		Assert(&mt, tmp, value)
+		require.False(t, mt.failed, mt.logs)
+
+		// the actual value is left untouched
+		require.Equal(t, filepath.Join(abs, "main.json"), value.Paths["main"])
+
+		config, err := os.ReadFile(filepath.Join(tmp, "config-path.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "config/app.json", string(config))
+
+		paths, err := os.ReadFile(filepath.Join(tmp, "paths.json"))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"main": "main.json", "shared": "/etc/shared.json"}`, string(paths))
+
+		// and the saved files load the same values again
+		updateGolden = false
+
+		mt = mockT{}
+		Assert(&mt, tmp, value)
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		type test struct {
+			Paths struct{ Main string } `testdata:"paths.json,resolvepaths"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/paths", new(test))
+
+		require.EqualValues(t, []string{
+			"[GoT] Load: *got.test.Paths: resolvepaths requires string values, but got struct { Main string }",
+		}, mt.logs)
+	})
+}
@@ -0,0 +1,145 @@
+package got
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/structtag"
+)
+
+// pipeline is the set of options which transform the raw contents of a file,
+// which always compose in the same order regardless of how they are listed in
+// the struct tag. When loading, the steps are:
+//
+//  1. "gzip" decompresses the file, where a ".gz" extension is ignored when
+//     choosing the formatter and codec (eg: "data.json.gz" is decoded as JSON)
+//  2. "expand" replaces ${VAR} and $VAR with environment variables
+//  3. "trim" removes leading and trailing whitespace
+//  4. "text" removes the trailing newline added when saving, but only when
+//     the contents look like text (see isText) so binary data is left as-is
+//
+// After which the contents are formatted, split (for front matter or concat)
+// and decoded as usual. When saving, the contents are encoded and formatted
+// before being trimmed, given a trailing newline and then compressed.
+// Expanding cannot be reversed, so fields using it are never saved.
+//
+// There is no "base64" option, since a ".b64" extension already decodes the
+// contents as part of the codec (see codec.Layer), which happens after these
+// steps (eg: "data.json.b64.gz" with "gzip" is decompressed, then decoded from
+// base64 and then as JSON).
+type pipeline struct {
+	gzip   bool
+	expand bool
+	trim   bool
+	text   bool
+}
+
+const gzipExt = ".gz"
+
+func newPipeline(tag *structtag.Tag) pipeline {
+	return pipeline{
+		gzip:   tag.HasOption("gzip"),
+		expand: tag.HasOption("expand"),
+		trim:   tag.HasOption("trim"),
+		text:   tag.HasOption("text"),
+	}
+}
+
+// fieldPipeline is the same as newPipeline, but reads the struct tag of field
+// (which is a zero value when saving contents that are not from a field).
+func fieldPipeline(field reflect.StructField) pipeline {
+	tag, ok := fieldTag(field)
+	if !ok {
+		return pipeline{}
+	}
+
+	return newPipeline(tag)
+}
+
+// codecFile returns the name of file used to choose the formatter and codec.
+func (p pipeline) codecFile(file string) string {
+	if p.gzip {
+		return strings.TrimSuffix(file, gzipExt)
+	}
+	return file
+}
+
+// load applies the steps to data read from a file, where the decompressed
+// contents are limited to max bytes (when set) like the file itself.
+func (p pipeline) load(data []byte, max int64) ([]byte, error) {
+	if p.gzip && len(data) > 0 {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gzip error: %w", err)
+		}
+		defer r.Close()
+
+		var lr io.Reader = r
+		if max > 0 {
+			lr = io.LimitReader(r, max+1)
+		}
+
+		if data, err = io.ReadAll(lr); err != nil {
+			return nil, fmt.Errorf("gzip error: %w", err)
+		}
+
+		if max > 0 && int64(len(data)) > max {
+			return nil, fmt.Errorf("gzip error: exceeds max file size of %d bytes", max)
+		}
+	}
+
+	if p.expand {
+		data = []byte(os.ExpandEnv(string(data)))
+	}
+
+	if p.trim {
+		data = bytes.TrimSpace(data)
+	}
+
+	if p.text && isText(data) {
+		data = bytes.TrimSuffix(data, []byte("\n"))
+	}
+
+	return data, nil
+}
+
+// save applies the reversible steps to data to be written to a file.
+func (p pipeline) save(data []byte) ([]byte, error) {
+	if p.trim {
+		data = bytes.TrimSpace(data)
+	}
+
+	// the newline is always added (even when there is already one) so that
+	// the contents are unchanged when they are loaded again
+	if p.text && len(data) > 0 && isText(data) {
+		data = append(data[:len(data):len(data)], '\n')
+	}
+
+	if p.gzip && len(data) > 0 {
+		var b bytes.Buffer
+
+		w := gzip.NewWriter(&b)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("gzip error: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("gzip error: %w", err)
+		}
+
+		data = b.Bytes()
+	}
+
+	return data, nil
+}
+
+// isText reports whether data looks like text rather than binary data, which
+// is valid UTF-8 without any NUL bytes.
+func isText(data []byte) bool {
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) < 0
+}
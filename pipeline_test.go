@@ -0,0 +1,166 @@
+package got
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("gzip and expand", func(t *testing.T) {
+		t.Setenv("GOT_TEST_NAME", "alice")
+
+		type test struct {
+			Data user `testdata:"data.json.gz,expand,gzip"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/pipeline", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, test{Data: user{Name: "alice"}}, actual)
+		require.EqualValues(t, []string{
+			`[GoT] Load: *got.test.Data: loaded file "testdata/pipeline/data.json.gz" as JSON (size 22)`,
+		}, mt.logs)
+	})
+
+	t.Run("gzip only", func(t *testing.T) {
+		type test struct {
+			Data user `testdata:"data.json.gz,gzip"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/pipeline", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, test{Data: user{Name: "${GOT_TEST_NAME}"}}, actual)
+	})
+
+	t.Run("gzip max file size", func(t *testing.T) {
+		type test struct {
+			Data string `testdata:"data.txt.gz,gzip"`
+		}
+
+		dir := t.TempDir()
+
+		var b bytes.Buffer
+		w := gzip.NewWriter(&b)
+		_, err := w.Write(bytes.Repeat([]byte("a"), 1024))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "data.txt.gz"), b.Bytes(), 0644))
+
+		var mt mockT
+		Options{MaxFileSize: 100}.Load(&mt, dir, new(test))
+
+		require.True(t, mt.failed)
+		require.EqualValues(t, []string{
+			`[GoT] Load: *got.test.Data: file "` + filepath.Join(dir, "data.txt.gz") + `" gzip error: exceeds max file size of 100 bytes`,
+		}, mt.logs)
+	})
+
+	t.Run("save expand", func(t *testing.T) {
+		t.Setenv("GOT_TEST_NAME", "alice")
+
+		type test struct {
+			Data user `testdata:"data.json.gz,expand,gzip"`
+		}
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		Assert(&mt, dir, &test{Data: user{Name: "alice"}})
+
+		require.False(t, mt.failed, mt.logs)
+		require.EqualValues(t, []string{
+			`[GoT] Assert: *got.test.Data: skipped: field uses expand, which cannot be reversed`,
+		}, mt.logs)
+
+		_, err := os.Stat(filepath.Join(dir, "data.json.gz"))
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("trim", func(t *testing.T) {
+		type test struct {
+			Padded string `testdata:"padded.txt,trim"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/pipeline", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, test{Padded: "hello world"}, actual)
+	})
+
+	t.Run("save gzip", func(t *testing.T) {
+		type test struct {
+			Data user `testdata:"data.json.gz,gzip"`
+		}
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+		expected := &test{Data: user{Name: "bob"}}
+
+		var mt mockT
+		Assert(&mt, dir, expected)
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "data.json.gz"))
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x1f, 0x8b}, data[:2]) // gzip magic number
+
+		var actual test
+		Load(&mt, dir, &actual)
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, expected, &actual)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		type test struct {
+			Text   []byte `testdata:"text.txt,text"`
+			Binary []byte `testdata:"binary.bin,text"`
+		}
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+		expected := &test{
+			Text:   []byte("hello world"),
+			Binary: []byte{0x00, 0xff, 0xfe},
+		}
+
+		var mt mockT
+		Assert(&mt, dir, expected)
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "text.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello world\n", string(data))
+
+		data, err = os.ReadFile(filepath.Join(dir, "binary.bin"))
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x00, 0xff, 0xfe}, data)
+
+		var actual test
+		Load(&mt, dir, &actual)
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, expected, &actual)
+	})
+}
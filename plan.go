@@ -0,0 +1,56 @@
+package got
+
+import "path/filepath"
+
+// PlannedFile is a golden file which would be written by saving a value.
+type PlannedFile struct {
+	// Name is the path of the file relative to the dir it would be saved to,
+	// using forward slashes (eg: "input/a.json" for an explode map).
+	Name string
+
+	// Size is the number of bytes which would be written.
+	Size int
+}
+
+// PlanSave returns the files that would be written by saving value (a pointer
+// to a struct) to dir when updating golden files, without touching the disk.
+// Every field is encoded in memory exactly as Assert would save it, including
+// a file per key for explode maps. Files which would be removed because they
+// are empty are omitted.
+func PlanSave(dir string, value any) ([]PlannedFile, error) {
+	plan := &savePlan{dir: dir}
+	o := Options{plan: plan}
+
+	value, err := o.prepareActual(dir, value)
+	if err != nil {
+		return nil, err
+	}
+
+	// nothing is logged, since there is no test to log to
+	log := (&logger{prefix: "[GoT] PlanSave: "}).buffered()
+
+	if err := o.saveDir(log, dir, value); err != nil {
+		return nil, err
+	}
+
+	return plan.files, nil
+}
+
+// savePlan collects the files saved by Options.saveFile when planning.
+type savePlan struct {
+	dir   string
+	files []PlannedFile
+}
+
+// add records file, which is only called for files that would be written.
+func (p *savePlan) add(file string, data []byte) {
+	name, err := filepath.Rel(p.dir, file)
+	if err != nil {
+		name = file
+	}
+
+	p.files = append(p.files, PlannedFile{
+		Name: filepath.ToSlash(name),
+		Size: len(data),
+	})
+}
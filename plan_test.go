@@ -0,0 +1,38 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSave(t *testing.T) {
+	type test struct {
+		Count  int               `testdata:"count.txt"`
+		Inputs map[string]string `testdata:"inputs/*.txt,explode"`
+		Empty  string            `testdata:"empty.txt"`
+	}
+
+	dir := filepath.Join(t.TempDir(), "plan")
+
+	files, err := PlanSave(dir, &test{
+		Count:  42,
+		Inputs: map[string]string{"inputs/b.txt": "hello", "inputs/a.txt": "hi"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []PlannedFile{
+		{Name: "count.txt", Size: 2},
+		{Name: "inputs/a.txt", Size: 2},
+		{Name: "inputs/b.txt", Size: 5},
+	}, files)
+
+	_, err = os.Stat(dir)
+	require.True(t, os.IsNotExist(err), "dir should not be created")
+}
+
+func TestPlanSaveError(t *testing.T) {
+	_, err := PlanSave(t.TempDir(), nil)
+	require.EqualError(t, err, "input cannot be nil")
+}
@@ -0,0 +1,41 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// these are variables so tests can simulate other platforms
+var (
+	goos   = runtime.GOOS
+	goarch = runtime.GOARCH
+)
+
+// platformPattern matches the platform placeholders along with an optional
+// leading separator, which is removed to get the fallback file name.
+var platformPattern = regexp.MustCompile(`[_.-]?\{(goos|goarch)\}`)
+
+// expandPlatform replaces the "{goos}" and "{goarch}" placeholders in name with
+// the current platform (eg: "expected_{goos}.txt" becomes "expected_linux.txt").
+func expandPlatform(name string) string {
+	return strings.NewReplacer("{goos}", goos, "{goarch}", goarch).Replace(name)
+}
+
+// platformFile resolves name within dir, expanding any platform placeholders.
+// When the platform-specific file does not exist, the file with the
+// placeholders removed (eg: "expected.txt") is used instead.
+func platformFile(fsys fileSystem, dir, name string) string {
+	file := filepath.Join(dir, expandPlatform(name))
+	if !platformPattern.MatchString(name) {
+		return file
+	}
+
+	if _, err := fsys.Stat(file); os.IsNotExist(err) {
+		return filepath.Join(dir, platformPattern.ReplaceAllString(name, ""))
+	}
+
+	return file
+}
@@ -0,0 +1,85 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlatformPlaceholders(t *testing.T) {
+	type test struct {
+		Expected string `testdata:"expected_{goos}.txt"`
+		Arch     string `testdata:"arch-{goarch}.txt"`
+	}
+
+	spec := []struct {
+		goos, goarch string
+		expected     test
+	}{
+		{goos: "linux", goarch: "amd64", expected: test{Expected: "linux", Arch: "amd64"}},
+		{goos: "windows", goarch: "arm64", expected: test{Expected: "windows"}},
+		{goos: "darwin", goarch: "amd64", expected: test{Expected: "default", Arch: "amd64"}},
+	}
+
+	for _, s := range spec {
+		t.Run(s.goos+"/"+s.goarch, func(t *testing.T) {
+			setPlatform(t, s.goos, s.goarch)
+
+			var mt mockT
+			var actual test
+			Load(&mt, "testdata/platform", &actual)
+
+			require.False(t, mt.failed, mt.logs)
+			require.EqualValues(t, s.expected, actual)
+		})
+	}
+
+	t.Run("save", func(t *testing.T) {
+		setPlatform(t, "windows", "amd64")
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		t.Run("fallback", func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "expected.txt"), []byte("stale"), 0644))
+
+			var mt mockT
+			Assert(&mt, dir, &test{Expected: "windows"})
+			require.False(t, mt.failed, mt.logs)
+
+			data, err := os.ReadFile(filepath.Join(dir, "expected.txt"))
+			require.NoError(t, err)
+			require.Equal(t, "windows", string(data))
+
+			_, err = os.Stat(filepath.Join(dir, "expected_windows.txt"))
+			require.True(t, os.IsNotExist(err), "platform-specific file should not be created")
+		})
+
+		t.Run("platform-specific", func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "expected.txt"), []byte("default"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "expected_windows.txt"), []byte("stale"), 0644))
+
+			var mt mockT
+			Assert(&mt, dir, &test{Expected: "windows"})
+			require.False(t, mt.failed, mt.logs)
+
+			data, err := os.ReadFile(filepath.Join(dir, "expected_windows.txt"))
+			require.NoError(t, err)
+			require.Equal(t, "windows", string(data))
+
+			data, err = os.ReadFile(filepath.Join(dir, "expected.txt"))
+			require.NoError(t, err)
+			require.Equal(t, "default", string(data))
+		})
+	})
+}
+
+func setPlatform(t *testing.T, os, arch string) {
+	prevOS, prevArch := goos, goarch
+	goos, goarch = os, arch
+	t.Cleanup(func() { goos, goarch = prevOS, prevArch })
+}
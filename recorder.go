@@ -0,0 +1,84 @@
+package got
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+var _ tester = (*Recorder)(nil)
+
+// Recorder can be used in place of *testing.T to capture the log lines
+// produced by GoT, so a test can assert on them (eg: to check which files were
+// loaded and in what order). It is safe for concurrent use.
+//
+// Failures are recorded rather than stopping the test, so Failed should be
+// checked afterwards. Subtests are not supported, so Run always fails.
+type Recorder struct {
+	mu     sync.Mutex
+	logs   []string
+	failed bool
+}
+
+// CaptureLogs calls fn with a new Recorder and returns the log lines it
+// captured.
+func CaptureLogs(fn func(t *Recorder)) []string {
+	var r Recorder
+	fn(&r)
+	return r.Logs()
+}
+
+// Logs returns a copy of the log lines captured so far.
+func (r *Recorder) Logs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.logs...)
+}
+
+// Failed reports whether Fatal or Fatalf has been called.
+func (r *Recorder) Failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.failed
+}
+
+func (r *Recorder) Helper() {}
+
+func (r *Recorder) Log(args ...any) {
+	r.log(fmt.Sprint(args...))
+}
+
+func (r *Recorder) Logf(msg string, args ...any) {
+	r.log(fmt.Sprintf(msg, args...))
+}
+
+func (r *Recorder) Fatal(args ...any) {
+	r.Log(args...)
+	r.fail()
+}
+
+func (r *Recorder) Fatalf(msg string, args ...any) {
+	r.Logf(msg, args...)
+	r.fail()
+}
+
+func (r *Recorder) Run(name string, fn func(t *testing.T)) bool {
+	r.Fatalf("[GoT] Recorder: cannot run subtest %q", name)
+	return false
+}
+
+func (r *Recorder) log(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logs = append(r.logs, msg)
+}
+
+func (r *Recorder) fail() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failed = true
+}
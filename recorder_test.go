@@ -0,0 +1,61 @@
+package got
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureLogs(t *testing.T) {
+	type test struct {
+		A string `testdata:"a.txt"`
+		B string `testdata:"b.txt"`
+	}
+
+	var actual test
+	logs := CaptureLogs(func(t *Recorder) {
+		Load(t, "testdata/multiple", &actual)
+	})
+
+	require.EqualValues(t, test{A: "A", B: "B"}, actual)
+	require.EqualValues(t, []string{
+		`[GoT] Load: *got.test.A: loaded file "testdata/multiple/a.txt" as string (size 1)`,
+		`[GoT] Load: *got.test.B: loaded file "testdata/multiple/b.txt" as string (size 1)`,
+	}, logs)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("failure", func(t *testing.T) {
+		var r Recorder
+		Load(&r, "testdata/text")
+
+		require.True(t, r.Failed())
+		require.EqualValues(t, []string{"[GoT] Load: at least 1 output required"}, r.Logs())
+	})
+
+	t.Run("subtest", func(t *testing.T) {
+		var r Recorder
+		require.False(t, r.Run("test-case-1", func(t *testing.T) {}))
+
+		require.True(t, r.Failed())
+		require.EqualValues(t, []string{`[GoT] Recorder: cannot run subtest "test-case-1"`}, r.Logs())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var r Recorder
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.Logf("hello %s", "world")
+			}()
+		}
+		wg.Wait()
+
+		require.False(t, r.Failed())
+		require.Len(t, r.Logs(), 10)
+	})
+}
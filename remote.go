@@ -0,0 +1,81 @@
+package got
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/fatih/structtag"
+)
+
+// isRemote reports whether the file name from a struct tag is an http:// or
+// https:// URL, which is fetched with Options.HTTPClient rather than read from
+// the input dirs. The response is decoded using the codec for its Content-Type
+// (eg: "application/json"), or for the extension of the URL otherwise.
+//
+// Remote files are never saved, so they are compared (like the "frozen"
+// option) rather than updated when updating golden files.
+func isRemote(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+func (o Options) loadRemote(log *logger, tag *structtag.Tag, field reflect.StructField, value reflect.Value) error {
+	if o.HTTPClient == nil {
+		return fmt.Errorf("remote file %q requires Options.HTTPClient", tag.Name)
+	}
+
+	u, err := url.Parse(tag.Name)
+	if err != nil {
+		return fmt.Errorf("invalid remote file %q: %w", tag.Name, err)
+	}
+
+	limit, err := getLimit(field.Type, tag)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.HTTPClient.Get(u.String())
+	if err != nil {
+		return fmt.Errorf("remote file %q request error: %w", tag.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote file %q returned status %s", tag.Name, resp.Status)
+	}
+
+	var r io.Reader = resp.Body
+	if o.MaxFileSize > 0 {
+		r = io.LimitReader(resp.Body, o.MaxFileSize+1)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("remote file %q read error: %w", tag.Name, err)
+	}
+
+	if o.MaxFileSize > 0 && int64(len(data)) > o.MaxFileSize {
+		return fmt.Errorf("remote file %q exceeds max file size of %d bytes", tag.Name, o.MaxFileSize)
+	}
+
+	// the content type takes precedence over the extension of the URL
+	codecFile := path.Base(u.Path)
+	if ext, ok := codec.ExtByContentType(resp.Header.Get("Content-Type")); ok && path.Ext(codecFile) != ext {
+		codecFile += ext
+	}
+
+	opts := fileOptions{
+		part:      frontMatterPart(tag),
+		limit:     limit,
+		field:     field,
+		pipeline:  newPipeline(tag),
+		codecFile: codecFile,
+	}
+
+	return o.decodeFile(log, tag.Name, data, opts, value)
+}
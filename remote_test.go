@@ -0,0 +1,145 @@
+package got
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// serverTransport sends every request to a test server, regardless of the
+// host in the URL.
+type serverTransport struct {
+	url *url.URL
+}
+
+func (s serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = s.url.Scheme
+	req.URL.Host = s.url.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRemote(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"alice"}`))
+	})
+	mux.HandleFunc("/schemas/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.Write([]byte(`{"name":"bob"}`))
+	})
+	mux.HandleFunc("/user.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("name: carol\n"))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	opts := Options{HTTPClient: &http.Client{Transport: serverTransport{url: u}}}
+
+	type user struct {
+		Name string `json:"name" yaml:"name"`
+	}
+
+	type test struct {
+		ByContentType user   `testdata:"https://registry.test/user.json"`
+		BySuffix      user   `testdata:"https://registry.test/schemas/user"`
+		ByExtension   user   `testdata:"https://registry.test/user.yaml"`
+		Raw           string `testdata:"https://registry.test/user.json"`
+	}
+
+	t.Run("load", func(t *testing.T) {
+		var mt mockT
+		var actual test
+		opts.Load(&mt, "testdata/text", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, test{
+			ByContentType: user{Name: "alice"},
+			BySuffix:      user{Name: "bob"},
+			ByExtension:   user{Name: "carol"},
+			Raw:           `{"name":"alice"}`,
+		}, actual)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		type test struct {
+			Missing user `testdata:"https://registry.test/missing.json"`
+		}
+
+		var mt mockT
+		opts.Load(&mt, "testdata/text", new(test))
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				`[GoT] Load: *got.test.Missing: remote file "https://registry.test/missing.json" returned status 404 Not Found`,
+			},
+		}, mt)
+	})
+
+	t.Run("requires client", func(t *testing.T) {
+		var mt mockT
+		Options{}.Load(&mt, "testdata/text", new(test))
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				`[GoT] Load: *got.test.ByContentType: remote file "https://registry.test/user.json" requires Options.HTTPClient`,
+			},
+		}, mt)
+	})
+
+	t.Run("assert", func(t *testing.T) {
+		type test struct {
+			Output string `testdata:"output.txt"`
+			User   user   `testdata:"https://registry.test/user.json"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "output.txt"), []byte("hello"), 0644))
+
+		var mt mockT
+		opts.Assert(&mt, dir, &test{Output: "hello", User: user{Name: "alice"}})
+		require.False(t, mt.failed, mt.logs)
+
+		mt = mockT{}
+		opts.Assert(&mt, dir, &test{Output: "hello", User: user{Name: "bob"}})
+		require.True(t, mt.failed)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		type test struct {
+			User user `testdata:"https://registry.test/user.json"`
+		}
+
+		dir := t.TempDir()
+
+		var mt mockT
+		opts.Assert(&mt, dir, &test{User: user{Name: "alice"}})
+		require.False(t, mt.failed, mt.logs)
+		require.Contains(t, mt.logs, `[GoT] Assert: *got.test.User: skipped: remote file "https://registry.test/user.json" cannot be updated`)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		mt = mockT{}
+		opts.Assert(&mt, dir, &test{User: user{Name: "bob"}})
+		require.True(t, mt.failed)
+	})
+}
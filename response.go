@@ -0,0 +1,112 @@
+package got
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/google/go-cmp/cmp"
+)
+
+// AssertResponse compares the body of resp against the golden file, or updates
+// it when the "update-golden" flag is provided. The body is decoded using the
+// codec for its Content-Type (eg: "application/json") and compared to the
+// golden file decoded using the codec for its extension, so formatting is not
+// significant. Bodies without a registered codec are compared as strings.
+//
+// The status code and content type are compared against a sibling metadata
+// file with a ".meta.json" extension (eg: "testdata/user.json" uses
+// "testdata/user.meta.json").
+func AssertResponse(t tester, file string, resp *http.Response) {
+	t.Helper()
+
+	log := &logger{
+		t:      t,
+		prefix: "[GoT] AssertResponse: ",
+	}
+
+	if err := (Options{}).assertResponse(log, file, resp); err != nil {
+		t.Fatalf("[GoT] AssertResponse: %s", err.Error())
+	}
+}
+
+// responseMeta is the contents of the metadata file for AssertResponse.
+type responseMeta struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+func (o Options) assertResponse(log *logger, file string, resp *http.Response) error {
+	if resp == nil {
+		return errors.New("response cannot be nil")
+	}
+
+	if updateGolden && os.Getenv(lockEnv) != "" {
+		return fmt.Errorf("golden files cannot be updated while %s is set", lockEnv)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	actual, err := decodeResponse(resp.Header.Get("Content-Type"), body)
+	if err != nil {
+		return err
+	}
+
+	meta := responseMeta{Status: resp.StatusCode, ContentType: resp.Header.Get("Content-Type")}
+	metaFile := strings.TrimSuffix(file, filepath.Ext(file)) + ".meta.json"
+
+	if updateGolden {
+		if err := o.saveFile(log, file, reflect.StructField{}, actual); err != nil {
+			return err
+		}
+		return o.saveFile(log, metaFile, reflect.StructField{}, reflect.ValueOf(meta))
+	}
+
+	expected := reflect.New(actual.Type()).Elem()
+	if err := o.loadFile(log, file, fileOptions{}, expected); err != nil {
+		return err
+	}
+
+	var expectedMeta responseMeta
+	if err := o.loadFile(log, metaFile, fileOptions{}, reflect.ValueOf(&expectedMeta).Elem()); err != nil {
+		return err
+	}
+
+	if !cmp.Equal(expectedMeta, meta) {
+		return fmt.Errorf("response metadata does not match %s: %s", metaFile, cmp.Diff(expectedMeta, meta))
+	}
+
+	opts := compareOptions(actual.Interface())
+
+	if !cmp.Equal(expected.Interface(), actual.Interface(), opts...) {
+		return fmt.Errorf("response body does not match %s: %s", file, cmp.Diff(expected.Interface(), actual.Interface(), opts...))
+	}
+
+	return nil
+}
+
+// decodeResponse decodes body using the codec for contentType, returning the
+// body as a string when there is no registered codec.
+func decodeResponse(contentType string, body []byte) (reflect.Value, error) {
+	c, err := codec.GetByContentType(contentType)
+	if err != nil || len(body) == 0 {
+		return reflect.ValueOf(string(body)), nil
+	}
+
+	v := reflect.New(reflect.TypeOf((*any)(nil)).Elem())
+	if err := c.Unmarshal(body, v.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to decode response body as %s: %w", c.Name(), err)
+	}
+
+	return v.Elem(), nil
+}
@@ -0,0 +1,65 @@
+package got
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertResponse(t *testing.T) {
+	response := func(status int, contentType, body string) *http.Response {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", contentType)
+		rec.WriteHeader(status)
+		rec.WriteString(body)
+		return rec.Result()
+	}
+
+	t.Run("match", func(t *testing.T) {
+		var mt mockT
+		AssertResponse(&mt, "testdata/response/user.json", response(200, "application/json", `{"name":"alice","id":1}`))
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("body mismatch", func(t *testing.T) {
+		var mt mockT
+		AssertResponse(&mt, "testdata/response/user.json", response(200, "application/json", `{"name":"bob","id":1}`))
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "[GoT] AssertResponse: response body does not match testdata/response/user.json")
+	})
+
+	t.Run("status mismatch", func(t *testing.T) {
+		var mt mockT
+		AssertResponse(&mt, "testdata/response/user.json", response(404, "application/json", `{"name":"alice","id":1}`))
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "[GoT] AssertResponse: response metadata does not match testdata/response/user.meta.json")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		AssertResponse(&mt, filepath.Join(dir, "user.json"), response(200, "application/json", `{"name":"alice","id":1}`))
+		require.False(t, mt.failed, mt.logs)
+
+		for _, file := range []string{"user.json", "user.meta.json"} {
+			actual, err := os.ReadFile(filepath.Join(dir, file))
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile(filepath.Join("testdata/response", file))
+			require.NoError(t, err)
+
+			require.Equal(t, string(expected), string(actual))
+		}
+	})
+}
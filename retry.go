@@ -0,0 +1,137 @@
+package got
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var _ tester = (*Attempt)(nil)
+
+// Attempt is used in place of *testing.T for a single attempt at running a
+// test case via TestSuite.RetryFunc, which captures failures so the attempt
+// can be retried without failing the test itself. Fatal and FailNow stop the
+// attempt (like *testing.T), while Error records the failure and continues.
+// Subtests are not supported, so Run always fails.
+type Attempt struct {
+	mu     sync.Mutex
+	logs   []string
+	failed bool
+}
+
+// Failed reports whether the attempt has failed.
+func (a *Attempt) Failed() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.failed
+}
+
+func (a *Attempt) Helper() {}
+
+func (a *Attempt) Log(args ...any) {
+	a.log(fmt.Sprint(args...))
+}
+
+func (a *Attempt) Logf(msg string, args ...any) {
+	a.log(fmt.Sprintf(msg, args...))
+}
+
+func (a *Attempt) Error(args ...any) {
+	a.Log(args...)
+	a.Fail()
+}
+
+func (a *Attempt) Errorf(msg string, args ...any) {
+	a.Logf(msg, args...)
+	a.Fail()
+}
+
+func (a *Attempt) Fatal(args ...any) {
+	a.Log(args...)
+	a.FailNow()
+}
+
+func (a *Attempt) Fatalf(msg string, args ...any) {
+	a.Logf(msg, args...)
+	a.FailNow()
+}
+
+// Fail marks the attempt as failed, but continues running it.
+func (a *Attempt) Fail() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.failed = true
+}
+
+// FailNow marks the attempt as failed and stops running it, which must be
+// called from the goroutine running the attempt.
+func (a *Attempt) FailNow() {
+	a.Fail()
+	runtime.Goexit()
+}
+
+func (a *Attempt) Run(name string, fn func(t *testing.T)) bool {
+	a.Fatalf("[GoT] Attempt: cannot run subtest %q", name)
+	return false
+}
+
+func (a *Attempt) log(msg string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.logs = append(a.logs, msg)
+}
+
+// runAttempts calls RetryFunc for tc until an attempt passes, making up to
+// Retries additional attempts. The logs of each failed attempt are reported,
+// along with those of the attempt which passed.
+func (s *TestSuite) runAttempts(t tester, tc TestCase) {
+	t.Helper()
+
+	attempts := s.Retries + 1
+
+	for i := 1; i <= attempts; i++ {
+		a := runAttempt(s.RetryFunc, tc)
+
+		if !a.Failed() {
+			for _, line := range a.logs {
+				t.Logf("%s", line)
+			}
+
+			if i > 1 {
+				t.Logf("[GoT] TestSuite: passed on attempt %d of %d", i, attempts)
+			}
+
+			return
+		}
+
+		t.Logf("[GoT] TestSuite: attempt %d of %d failed:\n%s", i, attempts, strings.Join(a.logs, "\n"))
+	}
+
+	t.Fatalf("[GoT] TestSuite: all %d attempts failed", attempts)
+}
+
+// runAttempt calls fn in a new goroutine, so that FailNow can stop it without
+// stopping the test, and recovers from any panic as a failure.
+func runAttempt(fn func(*Attempt, TestCase), tc TestCase) *Attempt {
+	a := new(Attempt)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				a.Errorf("panic: %v", r)
+			}
+		}()
+
+		fn(a, tc)
+	}()
+
+	<-done
+	return a
+}
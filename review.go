@@ -0,0 +1,112 @@
+package got
+
+import (
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/fatih/structtag"
+	"github.com/google/go-cmp/cmp"
+)
+
+// ReviewFunc decides whether the golden file at file should be updated with
+// the actual value, given the diff between them (see Options.Review).
+type ReviewFunc func(file string, diff string) bool
+
+// reviewFiles passes each golden file of actual that does not match expected
+// to o.Review, saving the actual value for those which are accepted. It
+// reports whether every mismatch was accepted, in which case the assertion can
+// be treated as passing.
+func (o Options) reviewFiles(log *logger, dir string, expected, actual any, opts []cmp.Option) (bool, error) {
+	want := reflect.ValueOf(expected).Elem()
+	resolved := true
+
+	review := func(log *logger, file string, field reflect.StructField, expected, actual reflect.Value) error {
+		if cmp.Equal(expected.Interface(), actual.Interface(), opts...) {
+			return nil
+		}
+
+		if !o.Review(file, cmp.Diff(expected.Interface(), actual.Interface(), opts...)) {
+			log.Log("rejected update of file %q", file)
+			resolved = false
+			return nil
+		}
+
+		return o.saveFile(log, file, field, actual)
+	}
+
+	err := walkDirFields(dir, actual, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		expectedValue := want.FieldByIndex(field.Index)
+
+		if frontMatterPart(tag) != "" || tag.HasOption(concatOption) || tag.HasOption(frozenOption) || isRemote(tag.Name) || isExplodeSlice(field.Type, tag) || tag.HasOption("expand") {
+			// either only part of a file (so it cannot be reviewed on it's own),
+			// a file which must never be updated (including templates whose
+			// expansion cannot be reversed) or files which are numbered by their
+			// position in a slice
+			if !cmp.Equal(expectedValue.Interface(), value.Interface(), opts...) {
+				resolved = false
+			}
+			return nil
+		}
+
+		log := log.WithPrefix("." + fieldName(field, tag))
+
+		if isMap(field.Type) && tag.HasOption("explode") {
+			for _, key := range mapKeysUnion(expectedValue, value) {
+				file := explodeFile(dir, tag, key.String())
+
+				if err := review(log, file, field, mapIndexOrZero(expectedValue, key), mapIndexOrZero(value, key)); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		file := platformFile(osFS, dir, alternateName(osFS, dir, testdataFile(field, tag, value)))
+
+		return review(log, file, field, expectedValue, value)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return resolved, nil
+}
+
+// mapKeysUnion returns the keys from both a and b (maps of the same type),
+// sorted so the files are reviewed in a stable order.
+func mapKeysUnion(a, b reflect.Value) []reflect.Value {
+	seen := make(map[string]bool)
+
+	var keys []reflect.Value
+	for _, m := range []reflect.Value{a, b} {
+		for _, key := range m.MapKeys() {
+			if !seen[key.String()] {
+				seen[key.String()] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	return keys
+}
+
+// mapIndexOrZero returns the value for key in m, or the zero value of the map's
+// element type when it is missing (which removes the file when saved).
+func mapIndexOrZero(m reflect.Value, key reflect.Value) reflect.Value {
+	if v := m.MapIndex(key); v.IsValid() {
+		return v
+	}
+	return reflect.Zero(m.Type().Elem())
+}
+
+// canReview reports whether mismatches should be passed to o.Review, which is
+// never the case while golden file updates are locked.
+func (o Options) canReview() bool {
+	return o.Review != nil && os.Getenv(lockEnv) == ""
+}
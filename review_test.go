@@ -0,0 +1,122 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsReview(t *testing.T) {
+	type test struct {
+		A string            `testdata:"a.txt"`
+		B string            `testdata:"b.txt"`
+		C map[string]string `testdata:"c/*.txt,explode"`
+	}
+
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("old a"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("old b"), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "c"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "c/x.txt"), []byte("old x"), 0644))
+		return dir
+	}
+
+	actual := &test{A: "new a", B: "new b", C: map[string]string{"c/y.txt": "new y"}}
+
+	readFile := func(t *testing.T, file string) string {
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			return ""
+		}
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("accept some", func(t *testing.T) {
+		dir := setup(t)
+
+		var reviewed []string
+		review := func(file, diff string) bool {
+			reviewed = append(reviewed, file)
+			require.NotEmpty(t, diff)
+			return file != filepath.Join(dir, "b.txt")
+		}
+
+		var mt mockT
+		Options{Review: review}.Assert(&mt, dir, actual)
+
+		require.True(t, mt.failed)
+		require.Equal(t, []string{
+			filepath.Join(dir, "a.txt"),
+			filepath.Join(dir, "b.txt"),
+			filepath.Join(dir, "c/x.txt"),
+			filepath.Join(dir, "c/y.txt"),
+		}, reviewed)
+
+		require.Equal(t, "new a", readFile(t, filepath.Join(dir, "a.txt")))
+		require.Equal(t, "old b", readFile(t, filepath.Join(dir, "b.txt")))
+		require.Equal(t, "", readFile(t, filepath.Join(dir, "c/x.txt")))
+		require.Equal(t, "new y", readFile(t, filepath.Join(dir, "c/y.txt")))
+	})
+
+	t.Run("accept all", func(t *testing.T) {
+		dir := setup(t)
+
+		var mt mockT
+		Options{Review: func(string, string) bool { return true }}.Assert(&mt, dir, actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "new b", readFile(t, filepath.Join(dir, "b.txt")))
+
+		// the files now match, so nothing is reviewed
+		Options{Review: func(file, _ string) bool {
+			require.Fail(t, "unexpected review", file)
+			return false
+		}}.Assert(&mt, dir, actual)
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		dir := setup(t)
+
+		var mt mockT
+		Options{}.Assert(&mt, dir, actual)
+
+		require.True(t, mt.failed)
+		require.Equal(t, "old a", readFile(t, filepath.Join(dir, "a.txt")))
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		type test struct {
+			Config string `testdata:"config.txt,expand"`
+		}
+
+		t.Setenv("RVHOME", "/home")
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config.txt"), []byte("home=${RVHOME}"), 0644))
+
+		var mt mockT
+		Options{Review: func(file, _ string) bool {
+			require.Fail(t, "unexpected review", file)
+			return true
+		}}.Assert(&mt, dir, &test{Config: "home=/other"})
+
+		require.True(t, mt.failed)
+		require.Equal(t, "home=${RVHOME}", readFile(t, filepath.Join(dir, "config.txt")))
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		t.Setenv(lockEnv, "1")
+		dir := setup(t)
+
+		var mt mockT
+		Options{Review: func(string, string) bool { return true }}.Assert(&mt, dir, actual)
+
+		require.True(t, mt.failed)
+		require.Equal(t, "old a", readFile(t, filepath.Join(dir, "a.txt")))
+	})
+}
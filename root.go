@@ -0,0 +1,37 @@
+package got
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// testDataRoot is the base directory configured by SetTestDataRoot.
+var testDataRoot string
+
+// SetTestDataRoot configures a base directory which is prepended to relative
+// directories given to Load, LoadDirs, Assert and TestSuite, so call sites in a
+// large repository can use short names (eg: after SetTestDataRoot("testdata"),
+// Load(t, "case-1", &input) reads from "testdata/case-1"). Directories which are
+// absolute or already within the root are left as-is, and the default of ""
+// leaves every directory as-is.
+//
+// This is typically called once from TestMain, since it applies to the whole
+// package.
+func SetTestDataRoot(root string) {
+	testDataRoot = root
+}
+
+// withTestDataRoot returns dir within the directory configured by
+// SetTestDataRoot.
+func withTestDataRoot(dir string) string {
+	if testDataRoot == "" || dir == "" || filepath.IsAbs(dir) {
+		return dir
+	}
+
+	root := filepath.Clean(testDataRoot)
+	if clean := filepath.Clean(dir); clean == root || strings.HasPrefix(clean, root+string(filepath.Separator)) {
+		return dir
+	}
+
+	return filepath.Join(root, dir)
+}
@@ -0,0 +1,81 @@
+package got
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTestDataRoot(t *testing.T) {
+	SetTestDataRoot("testdata")
+	t.Cleanup(func() { SetTestDataRoot("") })
+
+	type test struct {
+		Input string `testdata:"input.txt"`
+	}
+
+	t.Run("load", func(t *testing.T) {
+		var mt mockT
+		var actual test
+		Load(&mt, "text", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "hello world", actual.Input)
+		require.Equal(t, []string{`[GoT] Load: *got.test.Input: loaded file "testdata/text/input.txt" as string (size 11)`}, mt.logs)
+	})
+
+	t.Run("already rooted", func(t *testing.T) {
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/text", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "hello world", actual.Input)
+	})
+
+	t.Run("absolute", func(t *testing.T) {
+		dir, err := filepath.Abs("testdata/text")
+		require.NoError(t, err)
+
+		var mt mockT
+		var actual test
+		Load(&mt, dir, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "hello world", actual.Input)
+	})
+
+	t.Run("load dirs", func(t *testing.T) {
+		var mt mockT
+		var actual test
+		LoadDirs(&mt, []string{"multiple", "text"}, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "hello world", actual.Input)
+	})
+
+	t.Run("assert", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "text", &test{Input: "hello world"})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("suite", func(t *testing.T) {
+		suite := TestSuite{Dirs: []string{"suite/roots/unit"}}
+
+		require.Equal(t, []TestCase{
+			{Name: "unit-1", Dir: "testdata/suite/roots/unit/unit-1"},
+			{Name: "unit-2", Dir: "testdata/suite/roots/unit/unit-2"},
+		}, suite.Cases(t))
+	})
+
+	t.Run("suite shared dir", func(t *testing.T) {
+		suite := TestSuite{Dir: "suite/shared-dir/cases", SharedDir: "suite/shared-dir/common"}
+
+		for _, tc := range suite.Cases(t) {
+			require.Equal(t, "testdata/suite/shared-dir/common/"+tc.Name, tc.SharedDir)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package got
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// AssertRoundTrip ensures the fixtures in dir are stable when saved again,
+// which catches codecs (or fixtures) that do not survive a round-trip. A new
+// value of the same type as proto (a struct or a pointer to one) is loaded
+// from dir, saved to a temporary dir and then loaded again, failing the test
+// if the 2 loaded values differ.
+func AssertRoundTrip(t tester, dir string, proto any) {
+	t.Helper()
+
+	log := &logger{
+		t:      t,
+		prefix: "[GoT] AssertRoundTrip: ",
+	}
+
+	if err := (Options{}).assertRoundTrip(log, dir, proto); err != nil {
+		t.Fatalf("[GoT] AssertRoundTrip: %s", err.Error())
+	}
+}
+
+func (o Options) assertRoundTrip(log *logger, dir string, proto any) error {
+	if proto == nil {
+		return errors.New("proto cannot be nil")
+	}
+
+	typ := reflect.TypeOf(proto)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("proto must be a struct, but got %s", typ.Kind())
+	}
+
+	first := reflect.New(typ).Interface()
+	if err := o.loadDirs(log, []string{dir}, first); err != nil {
+		return err
+	}
+
+	if err := checkChannels(first); err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "got-roundtrip-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	o.saveFrozen = true
+	if err := o.saveDir(log, tmp, first); err != nil {
+		return err
+	}
+
+	second := reflect.New(typ).Interface()
+	if err := o.loadDirs(log, []string{tmp}, second); err != nil {
+		return err
+	}
+
+	opts := compareOptions(first)
+
+	if !cmp.Equal(first, second, opts...) {
+		return fmt.Errorf("round-trip of %s is not stable: %s", dir, cmp.Diff(first, second, opts...))
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package got
+
+import (
+	"testing"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/stretchr/testify/require"
+)
+
+// registerLossyCodec registers lossyCodec for ".lossy" until the test has
+// finished.
+func registerLossyCodec(t *testing.T) {
+	t.Cleanup(codec.Snapshot())
+	codec.Register(".lossy", new(lossyCodec))
+}
+
+// lossyCodec decodes JSON, but never encodes any of the data.
+type lossyCodec struct {
+	codec.JSONCodec
+}
+
+func (c *lossyCodec) Marshal(v any) ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("stable", func(t *testing.T) {
+		type test struct {
+			Input user `testdata:"input.json"`
+		}
+
+		var mt mockT
+		AssertRoundTrip(&mt, "testdata/roundtrip", test{})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		type test struct {
+			Input user `testdata:"input.json,frozen"`
+		}
+
+		var mt mockT
+		AssertRoundTrip(&mt, "testdata/roundtrip", test{})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("lossy", func(t *testing.T) {
+		registerLossyCodec(t)
+
+		type test struct {
+			Input user `testdata:"input.lossy"`
+		}
+
+		var mt mockT
+		AssertRoundTrip(&mt, "testdata/roundtrip", new(test))
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "[GoT] AssertRoundTrip: round-trip of testdata/roundtrip is not stable")
+	})
+
+	t.Run("invalid proto", func(t *testing.T) {
+		var mt mockT
+		AssertRoundTrip(&mt, "testdata/roundtrip", "hello")
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"[GoT] AssertRoundTrip: proto must be a struct, but got string",
+			},
+		}, mt)
+	})
+}
@@ -0,0 +1,112 @@
+package got
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// isScalar reports whether typ is a bool or number (or a pointer to one),
+// which can be loaded from and saved to a plain text file (eg: "count.txt")
+// when the file extension has no registered codec. A pointer is left nil when
+// the file is missing or empty, so it can be distinguished from the zero value.
+func isScalar(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseScalar sets value (where isScalar is true) by parsing the text in data,
+// ignoring any surrounding whitespace.
+func parseScalar(data []byte, value reflect.Value) error {
+	s := string(bytes.TrimSpace(data))
+
+	if value.Kind() == reflect.Ptr {
+		if s == "" {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		}
+
+		p := reflect.New(value.Type().Elem())
+		if err := parseScalar(data, p.Elem()); err != nil {
+			return err
+		}
+		value.Set(p)
+		return nil
+	}
+
+	switch {
+	case value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid bool %q", s)
+		}
+		value.SetBool(b)
+	case value.CanInt():
+		i, err := strconv.ParseInt(s, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", value.Type(), s)
+		}
+		value.SetInt(i)
+	case value.CanUint():
+		u, err := strconv.ParseUint(s, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", value.Type(), s)
+		}
+		value.SetUint(u)
+	case value.CanFloat():
+		f, err := strconv.ParseFloat(s, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", value.Type(), s)
+		}
+		value.SetFloat(f)
+	}
+
+	return nil
+}
+
+// isNilScalar reports whether val is a nil pointer to a scalar saved to file as
+// plain text (ie: the file has no codec), which is saved as an empty file
+// rather than removed so the field is still left nil when loaded again.
+func (o Options) isNilScalar(file string, field reflect.StructField, val reflect.Value) bool {
+	if val.Kind() != reflect.Ptr || !val.IsNil() || !isScalar(val.Type()) {
+		return false
+	}
+
+	var uerr *unknownCodecError
+	_, err := o.getCodec(file, field)
+	return errors.As(err, &uerr)
+}
+
+// formatScalar is the inverse of parseScalar, where a nil pointer is empty.
+func formatScalar(value reflect.Value) []byte {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch {
+	case value.Kind() == reflect.Bool:
+		return strconv.AppendBool(nil, value.Bool())
+	case value.CanInt():
+		return strconv.AppendInt(nil, value.Int(), 10)
+	case value.CanUint():
+		return strconv.AppendUint(nil, value.Uint(), 10)
+	default:
+		return strconv.AppendFloat(nil, value.Float(), 'g', -1, value.Type().Bits())
+	}
+}
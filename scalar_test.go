@@ -0,0 +1,91 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadScalar(t *testing.T) {
+	t.Run("values", func(t *testing.T) {
+		type test struct {
+			Count   int     `testdata:"count.txt"`
+			Enabled bool    `testdata:"enabled.txt"`
+			Ratio   float64 `testdata:"ratio.txt"`
+		}
+
+		testLoadOne(t, "scalar", new(test), &test{Count: 42, Enabled: true, Ratio: 0.5}, []string{
+			`[GoT] Load: *got.test.Count: loaded file "testdata/scalar/count.txt" as text (size 3)`,
+			`[GoT] Load: *got.test.Enabled: loaded file "testdata/scalar/enabled.txt" as text (size 4)`,
+			`[GoT] Load: *got.test.Ratio: loaded file "testdata/scalar/ratio.txt" as text (size 3)`,
+		})
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		type test struct {
+			Count   *int  `testdata:"count.txt"`
+			Enabled *bool `testdata:"enabled.txt"`
+			Empty   *int  `testdata:"empty.txt"`
+			Missing *int  `testdata:"missing.txt"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/scalar", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.NotNil(t, actual.Count)
+		require.Equal(t, 42, *actual.Count)
+		require.NotNil(t, actual.Enabled)
+		require.True(t, *actual.Enabled)
+		require.Nil(t, actual.Empty)
+		require.Nil(t, actual.Missing)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		type test struct {
+			Count *int `testdata:"invalid.txt"`
+		}
+
+		testLoadError(t, "scalar", new(test), `[GoT] Load: *got.test.Count: file "testdata/scalar/invalid.txt" decode error: invalid int "forty-two"`)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		type test struct {
+			Count *int  `testdata:"count.txt"`
+			Zero  *int  `testdata:"zero.txt"`
+			Unset *int  `testdata:"unset.txt"`
+			Flag  *bool `testdata:"flag.txt"`
+		}
+
+		dir := t.TempDir()
+		count, zero, flag := 7, 0, false
+		expected := &test{Count: &count, Zero: &zero, Flag: &flag}
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		Assert(&mt, dir, expected)
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "count.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "7", string(data))
+
+		data, err = os.ReadFile(filepath.Join(dir, "zero.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "0", string(data))
+
+		// a nil pointer writes an empty file
+		data, err = os.ReadFile(filepath.Join(dir, "unset.txt"))
+		require.NoError(t, err)
+		require.Empty(t, data)
+
+		var actual test
+		Load(&mt, dir, &actual)
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, expected, &actual)
+	})
+}
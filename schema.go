@@ -0,0 +1,67 @@
+package got
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/dominicbarnes/got/v2/codec"
+	"github.com/fatih/structtag"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// getSchema compiles the JSON Schema referenced by the "schema" option (a path
+// relative to the input directory), returning nil when it is not set.
+func getSchema(fsys fileSystem, input string, tag *structtag.Tag) (*jsonschema.Schema, error) {
+	name, ok := getTagOption(tag, "schema")
+	if !ok {
+		return nil, nil
+	}
+
+	file := filepath.Join(input, name)
+
+	c := jsonschema.NewCompiler()
+	if fsys != osFS {
+		data, err := readFS(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile schema %s: %w", file, err)
+		}
+
+		if err := c.AddResource(file, bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("failed to compile schema %s: %w", file, err)
+		}
+	}
+
+	schema, err := c.Compile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema %s: %w", file, err)
+	}
+
+	return schema, nil
+}
+
+// validateSchema decodes data using c and validates the result against schema.
+// Since codecs other than JSON (eg: YAML) produce different Go types, the data
+// is normalized through JSON first.
+func validateSchema(c codec.Codec, schema *jsonschema.Schema, data []byte) error {
+	var generic any
+	if err := c.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	normalized, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(normalized))
+	d.UseNumber()
+
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+
+	return schema.Validate(v)
+}
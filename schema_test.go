@@ -0,0 +1,59 @@
+package got
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSchema(t *testing.T) {
+	type person struct {
+		Name string `json:"name" yaml:"name"`
+		Age  int    `json:"age" yaml:"age"`
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		type test struct {
+			JSON person `testdata:"valid.json,schema=schema.json"`
+			YAML person `testdata:"valid.yaml,schema=schema.json"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/schema", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.EqualValues(t, test{
+			JSON: person{Name: "alice", Age: 30},
+			YAML: person{Name: "bob", Age: 42},
+		}, actual)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		type test struct {
+			Input person `testdata:"invalid.json,schema=schema.json"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/schema", new(test))
+
+		require.True(t, mt.failed)
+		require.Len(t, mt.logs, 1)
+		require.Contains(t, mt.logs[0], `[GoT] Load: *got.test.Input: file "testdata/schema/invalid.json" schema error:`)
+		require.Contains(t, mt.logs[0], `'/age' does not validate with`)
+		require.Contains(t, mt.logs[0], `expected integer, but got string`)
+	})
+
+	t.Run("missing schema", func(t *testing.T) {
+		type test struct {
+			Input person `testdata:"valid.json,schema=missing.json"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/schema", new(test))
+
+		require.True(t, mt.failed)
+		require.Len(t, mt.logs, 1)
+		require.Contains(t, mt.logs[0], `[GoT] Load: *got.test.Input: failed to compile schema testdata/schema/missing.json:`)
+	})
+}
@@ -0,0 +1,46 @@
+package got
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/fatih/structtag"
+)
+
+// checkSkipUpdate ensures every field named by Options.SkipUpdate is a field
+// with a "testdata" struct tag of at least one of values, so typos do not
+// silently update the field they were meant to protect.
+func (o Options) checkSkipUpdate(values []any) error {
+	if len(o.SkipUpdate) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]bool)
+	for _, value := range values {
+		if value == nil || reflect.TypeOf(value).Kind() != reflect.Ptr {
+			continue // invalid values are reported elsewhere
+		}
+
+		err := walkFields(value, func(field reflect.StructField, _ reflect.Value, _ *structtag.Tag) error {
+			fields[field.Name] = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range o.SkipUpdate {
+		if !fields[name] {
+			return fmt.Errorf("SkipUpdate references unknown field %s", name)
+		}
+	}
+
+	return nil
+}
+
+// skipUpdate reports whether field is left untouched when updating golden
+// files, because it is named by Options.SkipUpdate.
+func (o Options) skipUpdate(field reflect.StructField) bool {
+	return updateGolden && contains(o.SkipUpdate, field.Name)
+}
@@ -1,11 +1,17 @@
 package got
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 // RunTestSuite is a helper for running a common test suite. The Input type
@@ -19,7 +25,7 @@ func RunTestSuite[Input any, Output any](t tester, dir string, fn func(t *testin
 	t.Helper()
 
 	suite := TestSuite{
-		Dir: dir,
+		Dirs: []string{dir},
 		TestFunc: func(t *testing.T, tc TestCase) {
 			t.Helper()
 
@@ -35,6 +41,97 @@ func RunTestSuite[Input any, Output any](t tester, dir string, fn func(t *testin
 	suite.Run(t)
 }
 
+// RunTypedTestSuite is like RunTestSuite, but for heterogeneous suites where
+// the output type varies between test cases. Each test case must include a
+// "case.json" file with a "type" property naming a type that was added via
+// RegisterType. The passed func receives a pointer to a new value of that type
+// to populate, which is then passed to Assert.
+func RunTypedTestSuite[Input any](t tester, dir string, fn func(t *testing.T, tc TestCase, test Input, output any)) {
+	t.Helper()
+
+	suite := TestSuite{
+		Dirs: []string{dir},
+		TestFunc: func(t *testing.T, tc TestCase) {
+			t.Helper()
+
+			var input Input
+			tc.Load(t, &input)
+
+			output := tc.NewOutput(t)
+
+			fn(t, tc, input, output)
+
+			tc.Assert(t, output)
+		},
+	}
+
+	suite.Run(t)
+}
+
+var (
+	typesMu sync.RWMutex
+	types   = make(map[string]reflect.Type)
+)
+
+// RegisterType adds the type of value to the registry used by RunTypedTestSuite
+// and TestCase.NewOutput, identified by name. The value can either be a struct
+// or a pointer to one. It panics if value is nil or if name has already been
+// registered, use UnregisterType to replace it (eg: in a test cleanup).
+func RegisterType(name string, value any) {
+	if value == nil {
+		panic("got: RegisterType value is nil")
+	}
+
+	typ := reflect.TypeOf(value)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	typesMu.Lock()
+	defer typesMu.Unlock()
+
+	if _, ok := types[name]; ok {
+		panic(fmt.Sprintf("got: RegisterType called twice for type %q", name))
+	}
+
+	types[name] = typ
+}
+
+// UnregisterType removes the type registered with name, if any.
+func UnregisterType(name string) {
+	typesMu.Lock()
+	defer typesMu.Unlock()
+
+	delete(types, name)
+}
+
+// lookupType returns the type registered with name.
+func lookupType(name string) (reflect.Type, bool) {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+
+	typ, ok := types[name]
+	return typ, ok
+}
+
+// RegenerateAll runs each of the suites with golden file updates enabled, as if
+// the "update-golden" flag was provided, restoring the previous setting when
+// done. This allows a single test to regenerate all the golden files for a
+// package without affecting any other tests.
+//
+// Since the setting is global, the suites' TestFunc should not call t.Parallel.
+func RegenerateAll(t tester, suites ...*TestSuite) {
+	t.Helper()
+
+	prev := updateGolden
+	SetUpdateGolden(true)
+	defer SetUpdateGolden(prev)
+
+	for _, suite := range suites {
+		suite.Run(t)
+	}
+}
+
 // TestCase is used to wrap up test metadata.
 type TestCase struct {
 	// Name is the base name for this test case (excluding any parent names).
@@ -54,28 +151,103 @@ type TestCase struct {
 	// SharedDir is an alternate location for test case configuration, if the
 	// suite has been configured to search for this.
 	SharedDir string
+
+	// InputDir is an optional sub-directory used by Load, see TestSuite.InputDir.
+	InputDir string
+
+	// OutputDir is an optional sub-directory used by Assert, see
+	// TestSuite.OutputDir.
+	OutputDir string
+
+	// GoldenDir is an optional directory used by Assert instead of Dir, see
+	// TestSuite.GoldenDir.
+	GoldenDir string
+
+	// WorkDir is a fresh temporary directory for this test case to write to,
+	// which is removed once it has finished, see TestSuite.CreateWorkDir.
+	WorkDir string
 }
 
 // Load is a helper for loading testdata for this test case, factoring in a
 // SharedDir automatically if applicable.
 func (c TestCase) Load(t tester, values ...any) {
-	if c.SharedDir != "" {
-		LoadDirs(t, []string{c.SharedDir, c.Dir}, values...)
+	if dirs := c.LoadDirsList(); len(dirs) > 1 {
+		LoadDirs(t, dirs, values...)
 	} else {
-		Load(t, c.Dir, values...)
+		Load(t, dirs[0], values...)
+	}
+}
+
+// LoadDirsList returns the directories that Load reads from in order of
+// precedence (later directories override earlier ones), which is the SharedDir
+// (when set) followed by the Dir, each including the InputDir.
+func (c TestCase) LoadDirsList() []string {
+	if c.SharedDir != "" {
+		return []string{filepath.Join(c.SharedDir, c.InputDir), filepath.Join(c.Dir, c.InputDir)}
+	}
+
+	return []string{filepath.Join(c.Dir, c.InputDir)}
+}
+
+// caseFile is the file within a test case that describes it's metadata.
+const caseFile = "case.json"
+
+// NewOutput returns a pointer to a new value of the type named by the "type"
+// property in this test case's "case.json" file, which must have been added
+// via RegisterType.
+func (c TestCase) NewOutput(t tester) any {
+	t.Helper()
+
+	var meta struct {
+		Case struct {
+			Type string `json:"type"`
+		} `testdata:"case.json"`
+	}
+	c.Load(t, &meta)
+
+	if meta.Case.Type == "" {
+		dirs := c.LoadDirsList()
+		t.Fatalf("[GoT] NewOutput: %s is missing a type", filepath.Join(dirs[len(dirs)-1], caseFile))
+		return nil
 	}
+
+	typ, ok := lookupType(meta.Case.Type)
+	if !ok {
+		t.Fatalf("[GoT] NewOutput: type %q has not been registered", meta.Case.Type)
+		return nil
+	}
+
+	return reflect.New(typ).Interface()
 }
 
 // Assert is a helper for checking and/or saving testdata for this test case.
 func (c TestCase) Assert(t tester, values ...any) {
-	Assert(t, c.Dir, values...)
+	dir := c.Dir
+	if c.GoldenDir != "" {
+		dir = c.GoldenDir
+	}
+
+	Assert(t, filepath.Join(dir, c.OutputDir), values...)
 }
 
 // TestSuite defines a collection of tests backed by directories/files on disk.
 type TestSuite struct {
 	// Dir is the location of your test suite.
+	//
+	// Deprecated: Use Dirs instead, which supports multiple locations. When
+	// both are set, Dir is treated as the first entry of Dirs.
 	Dir string
 
+	// Dirs are the locations of your test suite, where the test cases found in
+	// each are merged into a single suite. Each test case name must be unique
+	// across all of the directories.
+	//
+	// Both Dir and Dirs accept glob patterns (eg: "testdata/*/cases"), which
+	// add every matching directory in lexical order. A pattern which does not
+	// match any directories fails the test, as does a test case name which is
+	// found under more than one of the matches.
+	Dirs []string
+
 	// SharedDir adds an additional directory to search for test cases.
 	//
 	// When set, this directory is scanned first and is treated as the primary
@@ -89,74 +261,443 @@ type TestSuite struct {
 	// configuration.
 	SharedDir string
 
+	// InputDir is an optional sub-directory within each test case which holds
+	// the inputs, so TestCase.Load will read from "<case>/<InputDir>" instead.
+	InputDir string
+
+	// OutputDir is an optional sub-directory within each test case which holds
+	// the expected outputs, so TestCase.Assert will compare against (or write
+	// to) "<case>/<OutputDir>" instead.
+	OutputDir string
+
+	// GoldenDir is an optional directory which holds the expected outputs in a
+	// tree parallel to the test cases, so TestCase.Assert will compare against
+	// (or write to) "<GoldenDir>/<name>" instead of the test case directory.
+	// The name excludes any ".skip" or ".only" suffix, and OutputDir is still
+	// applied within it.
+	GoldenDir string
+
+	// IndexFile is an optional file within the first of Dirs which lists the
+	// test cases to run, one directory name per line. When set, only the listed
+	// test cases are included and they are run in the listed order. Blank lines
+	// and lines starting with "#" are ignored.
+	IndexFile string
+
+	// When is an optional predicate for test cases, where any test case which
+	// it returns false for is skipped. Unlike Skip, this allows the decision to
+	// be made by the code running the suite (eg: an implementation which does
+	// not support some of the shared test cases), but those test cases are
+	// still included by Cases.
+	When func(TestCase) bool
+
+	// MinCases makes Run fail when fewer than this many test cases were run
+	// (ie: not skipped), which guards against an over-broad skip or filter
+	// silently excluding most of the suite.
+	MinCases int
+
+	// CreateWorkDir gives each test case its own empty TestCase.WorkDir (via
+	// t.TempDir) for any files it writes, so test cases which call t.Parallel
+	// cannot interfere with each other. It is removed automatically once the
+	// test case has finished.
+	CreateWorkDir bool
+
+	// RequireAllRun makes Run fail when any of the test cases were skipped,
+	// whether by Skip, Only, When or the test itself.
+	//
+	// Both this and MinCases count the test cases once they have finished, so
+	// they cannot be used when TestFunc calls t.Parallel.
+	RequireAllRun bool
+
 	// TestFunc is the hook for running test code, it will be called for each
 	// found test case in the suite.
 	TestFunc func(*testing.T, TestCase)
+
+	// RetryFunc is used instead of TestFunc when set, which is called with an
+	// Attempt rather than a *testing.T so that a failed attempt can be retried
+	// (see Retries).
+	RetryFunc func(*Attempt, TestCase)
+
+	// Retries is the number of additional attempts made for a test case which
+	// fails, where the test case passes if any of the attempts succeed. This
+	// requires RetryFunc, since a *testing.T cannot be reset once it fails.
+	//
+	// Retrying masks real flakiness (including bugs in the code under test),
+	// so this should be used sparingly and only for test cases which exercise
+	// inherently unreliable behavior (eg: an external service).
+	Retries int
 }
 
 // Run loads and executes the test suite.
 func (s *TestSuite) Run(t tester) {
 	t.Helper()
 
-	hasOnly := false
+	if s.Retries > 0 && s.RetryFunc == nil {
+		t.Fatalf("[GoT] TestSuite: Retries requires RetryFunc, since TestFunc cannot be retried")
+		return
+	}
+
+	testCases := s.Cases(t)
+	hasOnly := hasOnlyTestCase(testCases)
+
+	var ran int
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Helper()
+
+			defer func() {
+				if !t.Skipped() {
+					ran++
+				}
+				recordCase(t.Skipped(), t.Failed())
+			}()
+
+			if reason := skipReason(testCase, hasOnly, s.When); reason != "" {
+				t.Skip(reason)
+			}
+
+			if s.CreateWorkDir {
+				testCase.WorkDir = t.TempDir()
+			}
+
+			if s.RetryFunc != nil {
+				s.runAttempts(t, testCase)
+			} else {
+				s.TestFunc(t, testCase)
+			}
+		})
+	}
+
+	switch {
+	case s.RequireAllRun && ran < len(testCases):
+		t.Fatalf("[GoT] TestSuite: only %d of %d test cases were run, but all are required", ran, len(testCases))
+	case ran < s.MinCases:
+		t.Fatalf("[GoT] TestSuite: only %d of %d test cases were run, but at least %d are required", ran, len(testCases), s.MinCases)
+	}
+}
+
+// Cases returns the test cases found in the suite, sorted by name (or in the
+// order listed by IndexFile). This does not factor in Skip or Only, which is
+// left to the caller.
+func (s *TestSuite) Cases(t tester) []TestCase {
+	t.Helper()
+
 	testCases := make(map[string]TestCase)
 
-	for _, testDir := range listSubDirs(t, s.Dir) {
-		name, skip, only := parseTestDir(testDir)
-		if only {
-			hasOnly = true
-		}
+	roots, ok := s.roots(t)
+	if !ok {
+		return nil
+	}
 
-		testCase := TestCase{
-			Name: name,
-			Skip: skip,
-			Only: only,
-			Dir:  filepath.Join(s.Dir, testDir),
+	for _, root := range roots {
+		dirs, ok := parseTestDirs(t, root)
+		if !ok {
+			return nil
 		}
 
-		testCases[name] = testCase
-	}
+		for _, d := range dirs {
+			dir := filepath.Join(root, d.dir)
 
-	for _, testDir := range listSubDirs(t, s.SharedDir) {
-		name, skip, only := parseTestDir(testDir)
-		if only {
-			hasOnly = true
+			if prev, ok := testCases[d.name]; ok {
+				t.Fatalf("test case %s is defined more than once: %s and %s", d.name, prev.Dir, dir)
+				return nil
+			}
+
+			testCases[d.name] = TestCase{
+				Name:      d.name,
+				Skip:      d.skip,
+				Only:      d.only,
+				Dir:       dir,
+				InputDir:  s.InputDir,
+				OutputDir: s.OutputDir,
+			}
 		}
+	}
 
-		sharedDir := filepath.Join(s.SharedDir, testDir)
+	shared := withTestDataRoot(s.SharedDir)
+
+	sharedDirs, ok := parseTestDirs(t, shared)
+	if !ok {
+		return nil
+	}
 
-		if tc, ok := testCases[name]; !ok {
-			testCases[name] = TestCase{
-				Name:      name,
-				Skip:      skip,
-				Only:      only,
-				Dir:       filepath.Join(s.Dir, testDir),
+	for _, d := range sharedDirs {
+		sharedDir := filepath.Join(shared, d.dir)
+
+		if tc, ok := testCases[d.name]; !ok {
+			testCases[d.name] = TestCase{
+				Name:      d.name,
+				Skip:      d.skip,
+				Only:      d.only,
+				Dir:       filepath.Join(firstDir(roots), d.dir),
 				SharedDir: sharedDir,
+				InputDir:  s.InputDir,
+				OutputDir: s.OutputDir,
 			}
 		} else {
 			tc.SharedDir = sharedDir
 
+			testCases[d.name] = tc
+		}
+	}
+
+	if s.GoldenDir != "" {
+		golden := withTestDataRoot(s.GoldenDir)
+
+		for name, tc := range testCases {
+			tc.GoldenDir = filepath.Join(golden, name)
 			testCases[name] = tc
 		}
 	}
 
-	for _, testName := range getSortedTestNames(testCases) {
-		testCase := testCases[testName]
+	testNames := getSortedTestNames(testCases)
+
+	if s.IndexFile != "" {
+		indexFile := filepath.Join(firstDir(roots), s.IndexFile)
+
+		names, err := readIndexFile(indexFile)
+		if err != nil {
+			t.Fatalf("failed to read index file %s: %s", indexFile, err)
+			return nil
+		}
+
+		for _, name := range names {
+			if _, ok := testCases[name]; !ok {
+				t.Fatalf("index file %s lists unknown test case %s", indexFile, name)
+				return nil
+			}
+		}
+
+		testNames = names
+	}
+
+	list := make([]TestCase, 0, len(testNames))
+	for _, testName := range testNames {
+		list = append(list, testCases[testName])
+	}
+
+	return list
+}
+
+// roots returns the directories to search for test cases, including Dir, where
+// glob patterns are expanded to the matching directories in lexical order. The
+// root configured by SetTestDataRoot is applied first.
+func (s *TestSuite) roots(t tester) ([]string, bool) {
+	t.Helper()
+
+	dirs := s.Dirs
+	if s.Dir != "" {
+		dirs = append([]string{s.Dir}, s.Dirs...)
+	}
+
+	var roots []string
+	for _, dir := range dirs {
+		dir = withTestDataRoot(dir)
+
+		if !isGlob(dir) {
+			roots = append(roots, dir)
+			continue
+		}
+
+		matches, err := filepath.Glob(dir)
+		if err != nil {
+			t.Fatalf("invalid dir pattern %s: %s", dir, err)
+			return nil, false
+		}
+
+		var found bool
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				roots = append(roots, match)
+				found = true
+			}
+		}
+
+		if !found {
+			t.Fatalf("dir pattern %s does not match any directories", dir)
+			return nil, false
+		}
+	}
+
+	return roots, true
+}
+
+// isGlob reports whether dir contains any of the special characters used by
+// filepath.Match.
+func isGlob(dir string) bool {
+	return strings.ContainsAny(dir, "*?[")
+}
+
+// firstDir returns the first of dirs, which is where test cases which are only
+// found in SharedDir are expected to be added.
+func firstDir(dirs []string) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	return dirs[0]
+}
+
+// readIndexFile reads the test case names listed in file, ignoring blank lines
+// and comments. Each line may be a directory name including a ".skip" or
+// ".only" suffix, but only the test case name is returned.
+func readIndexFile(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, _, _ := parseTestDir(line)
+		if seen[name] {
+			return nil, fmt.Errorf("test case %s is listed more than once", name)
+		}
+		seen[name] = true
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// CompareTestSuite runs each test case in the suite through both a and b, which
+// are typically 2 implementations of a common interface, failing the test if
+// their outputs differ for any test case. Unlike Assert, this does not involve
+// golden files, the implementations are only compared to each other.
+//
+// When t supports Cleanup (eg: *testing.T), the outputs are compared once every
+// test case has finished, so a and b are free to call t.Parallel.
+func CompareTestSuite[Output any](t tester, s *TestSuite, a, b func(t *testing.T, tc TestCase) Output) {
+	t.Helper()
+
+	var mu sync.Mutex
+	outputsA := make(map[string]Output)
+	outputsB := make(map[string]Output)
+
+	compare := func() {
+		t.Helper()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		AssertEqualOutputs(t, outputsA, outputsB)
+	}
+
+	// cleanup runs after every subtest, including parallel ones, has completed
+	if c, ok := t.(interface{ Cleanup(func()) }); ok {
+		c.Cleanup(compare)
+	} else {
+		defer compare()
+	}
+
+	testCases := s.Cases(t)
+	hasOnly := hasOnlyTestCase(testCases)
+
+	for _, testCase := range testCases {
+		testCase := testCase
 
 		t.Run(testCase.Name, func(t *testing.T) {
 			t.Helper()
 
-			if hasOnly && !testCase.Only {
-				t.Skip("skipping test because it is excluded by only")
-			} else if testCase.Skip {
-				t.Skip("skipping test because it is has been marked")
+			if reason := skipReason(testCase, hasOnly, s.When); reason != "" {
+				t.Skip(reason)
 			}
 
-			s.TestFunc(t, testCase)
+			outputA := a(t, testCase)
+			outputB := b(t, testCase)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			outputsA[testCase.Name] = outputA
+			outputsB[testCase.Name] = outputB
 		})
 	}
 }
 
+// AssertEqualOutputs compares the outputs from 2 implementations keyed by the
+// test case name, failing the test with a diff for each test case that does not
+// match (including test cases missing from either side).
+func AssertEqualOutputs[Output any](t tester, a, b map[string]Output) {
+	t.Helper()
+
+	if err := compareOutputs(a, b); err != nil {
+		t.Fatalf("[GoT] AssertEqualOutputs: %s", err.Error())
+	}
+}
+
+func compareOutputs[Output any](a, b map[string]Output) error {
+	names := make(map[string]struct{})
+	for name := range a {
+		names[name] = struct{}{}
+	}
+	for name := range b {
+		names[name] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	opts := compareOptions(new(Output))
+
+	var failures []string
+	for _, name := range sorted {
+		outputA, okA := a[name]
+		outputB, okB := b[name]
+
+		switch {
+		case !okA:
+			failures = append(failures, fmt.Sprintf("test case %q is missing from a", name))
+		case !okB:
+			failures = append(failures, fmt.Sprintf("test case %q is missing from b", name))
+		case !cmp.Equal(outputA, outputB, opts...):
+			failures = append(failures, fmt.Sprintf("test case %q outputs differ: %s", name, cmp.Diff(outputA, outputB, opts...)))
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "\n"))
+	}
+
+	return nil
+}
+
+func hasOnlyTestCase(testCases []TestCase) bool {
+	for _, testCase := range testCases {
+		if testCase.Only {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipReason returns why the test case should be skipped, or an empty string
+// if it should be run.
+func skipReason(testCase TestCase, hasOnly bool, when func(TestCase) bool) string {
+	switch {
+	case hasOnly && !testCase.Only:
+		return "skipping test because it is excluded by only"
+	case testCase.Skip:
+		return "skipping test because it is has been marked"
+	case when != nil && !when(testCase):
+		return "skipping test because it is excluded by when"
+	default:
+		return ""
+	}
+}
+
 func getSortedTestNames(input map[string]TestCase) []string {
 	testNames := make([]string, 0, len(input))
 	for testName := range input {
@@ -188,13 +729,51 @@ func listSubDirs(t tester, dir string) []string {
 	return list
 }
 
+type parsedTestDir struct {
+	dir  string
+	name string
+	skip bool
+	only bool
+}
+
+// parseTestDirs lists and parses the test dirs within dir, failing the test if
+// any are marked as both skip and only, or if multiple test dirs resolve to the
+// same name (eg: "a.skip" and "a.only").
+func parseTestDirs(t tester, dir string) ([]parsedTestDir, bool) {
+	t.Helper()
+
+	var list []parsedTestDir
+	seen := make(map[string]string)
+
+	for _, d := range listSubDirs(t, dir) {
+		name, skip, only := parseTestDir(d)
+
+		if skip && only {
+			t.Fatalf("test case %s cannot be marked as both skip and only", filepath.Join(dir, d))
+			return nil, false
+		}
+
+		if prev, ok := seen[name]; ok {
+			t.Fatalf("test case %s is defined more than once: %s and %s", name, filepath.Join(dir, prev), filepath.Join(dir, d))
+			return nil, false
+		}
+		seen[name] = d
+
+		list = append(list, parsedTestDir{dir: d, name: name, skip: skip, only: only})
+	}
+
+	return list, true
+}
+
 // returns name, skip, only.
 func parseTestDir(input string) (string, bool, bool) {
 	switch {
 	case strings.HasSuffix(input, ".skip"):
-		return strings.TrimSuffix(input, ".skip"), true, false
+		name, _, only := parseTestDir(strings.TrimSuffix(input, ".skip"))
+		return name, true, only
 	case strings.HasSuffix(input, ".only"):
-		return strings.TrimSuffix(input, ".only"), false, true
+		name, skip, _ := parseTestDir(strings.TrimSuffix(input, ".only"))
+		return name, skip, true
 	default:
 		return input, false, false
 	}
@@ -1,7 +1,12 @@
 package got
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,6 +27,166 @@ func TestRunTestSuite(t *testing.T) {
 	})
 }
 
+func TestRunTypedTestSuite(t *testing.T) {
+	type Test struct {
+		Input string `testdata:"input.txt"`
+	}
+
+	type Upper struct {
+		Output string `testdata:"expected.txt"`
+	}
+
+	type Length struct {
+		Length int `testdata:"length.json"`
+	}
+
+	RegisterType("upper", Upper{})
+	RegisterType("length", new(Length))
+	t.Cleanup(func() {
+		UnregisterType("upper")
+		UnregisterType("length")
+	})
+
+	var outputs []any
+
+	RunTypedTestSuite(t, "testdata/suite/typed", func(t *testing.T, tc TestCase, test Test, output any) {
+		t.Helper()
+
+		switch o := output.(type) {
+		case *Upper:
+			o.Output = strings.ToUpper(test.Input)
+		case *Length:
+			o.Length = len(test.Input)
+		default:
+			t.Fatalf("unexpected output type %T", output)
+		}
+
+		outputs = append(outputs, output)
+	})
+
+	require.EqualValues(t, []any{
+		&Upper{Output: "HELLO WORLD"},
+		&Length{Length: 11},
+	}, outputs)
+}
+
+func TestTestCaseLoadDirsList(t *testing.T) {
+	t.Run("without shared dir", func(t *testing.T) {
+		tc := TestCase{Dir: "testdata/suite/single-case/test-case-1"}
+		require.Equal(t, []string{"testdata/suite/single-case/test-case-1"}, tc.LoadDirsList())
+	})
+
+	t.Run("with shared dir", func(t *testing.T) {
+		tc := TestCase{Dir: "testdata/cases/test-case-1", SharedDir: "testdata/shared/test-case-1", InputDir: "input"}
+		require.Equal(t, []string{
+			"testdata/shared/test-case-1/input",
+			"testdata/cases/test-case-1/input",
+		}, tc.LoadDirsList())
+	})
+}
+
+func TestTestCaseNewOutput(t *testing.T) {
+	t.Run("missing type", func(t *testing.T) {
+		var mt mockT
+		tc := TestCase{Dir: "testdata/suite/single-case/test-case-1"}
+
+		require.Nil(t, tc.NewOutput(&mt))
+		require.True(t, mt.failed)
+		require.Equal(t, "[GoT] NewOutput: testdata/suite/single-case/test-case-1/case.json is missing a type", mt.logs[len(mt.logs)-1])
+	})
+
+	t.Run("missing type in input dir", func(t *testing.T) {
+		var mt mockT
+		tc := TestCase{Dir: t.TempDir(), InputDir: "input"}
+
+		require.Nil(t, tc.NewOutput(&mt))
+		require.True(t, mt.failed)
+		require.Equal(t, "[GoT] NewOutput: "+filepath.Join(tc.Dir, "input", "case.json")+" is missing a type", mt.logs[len(mt.logs)-1])
+	})
+
+	t.Run("unregistered type", func(t *testing.T) {
+		var mt mockT
+		tc := TestCase{Dir: "testdata/suite/typed/test-case-1"}
+
+		require.Nil(t, tc.NewOutput(&mt))
+		require.True(t, mt.failed)
+		require.Equal(t, `[GoT] NewOutput: type "upper" has not been registered`, mt.logs[len(mt.logs)-1])
+	})
+}
+
+func TestRegisterType(t *testing.T) {
+	type Output struct{}
+
+	t.Run("nil", func(t *testing.T) {
+		require.PanicsWithValue(t, "got: RegisterType value is nil", func() {
+			RegisterType("nil", nil)
+		})
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		RegisterType("output", Output{})
+		t.Cleanup(func() { UnregisterType("output") })
+
+		require.PanicsWithValue(t, `got: RegisterType called twice for type "output"`, func() {
+			RegisterType("output", new(Output))
+		})
+	})
+
+	t.Run("unregister", func(t *testing.T) {
+		RegisterType("output", Output{})
+		UnregisterType("output")
+
+		_, ok := lookupType("output")
+		require.False(t, ok)
+
+		require.NotPanics(t, func() {
+			RegisterType("output", Output{})
+		})
+		UnregisterType("output")
+	})
+}
+
+func TestRegenerateAll(t *testing.T) {
+	type Test struct {
+		Input string `testdata:"input.txt"`
+	}
+
+	type Expected struct {
+		Output string `testdata:"expected.txt"`
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"test-case-1", "test-case-2"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "input.txt"), []byte(name), 0644))
+	}
+
+	suite := TestSuite{
+		Dir: dir,
+		TestFunc: func(t *testing.T, tc TestCase) {
+			t.Helper()
+
+			var test Test
+			tc.Load(t, &test)
+
+			tc.Assert(t, &Expected{Output: strings.ToUpper(test.Input)})
+		},
+	}
+
+	RegenerateAll(t, &suite)
+
+	require.False(t, updateGolden)
+
+	for _, name := range []string{"test-case-1", "test-case-2"} {
+		data, err := os.ReadFile(filepath.Join(dir, name, "expected.txt"))
+		require.NoError(t, err)
+		require.Equal(t, strings.ToUpper(name), string(data))
+	}
+
+	// goldens are now in place, so a regular run should pass
+	suite.Run(t)
+}
+
 func TestTestSuite(t *testing.T) {
 	t.Run("single case", func(t *testing.T) {
 		var mt mockT
@@ -155,6 +320,38 @@ func TestTestSuite(t *testing.T) {
 		}, mt)
 	})
 
+	t.Run("when", func(t *testing.T) {
+		var cases []TestCase
+
+		suite := TestSuite{
+			Dir: "testdata/suite/multiple-cases",
+			When: func(tc TestCase) bool {
+				return tc.Name != "test-case-2"
+			},
+			TestFunc: func(t *testing.T, tc TestCase) {
+				t.Helper()
+
+				cases = append(cases, tc)
+			},
+		}
+
+		suite.Run(t)
+
+		require.ElementsMatch(t, []TestCase{
+			{
+				Name: "test-case-1",
+				Dir:  "testdata/suite/multiple-cases/test-case-1",
+			},
+			{
+				Name: "test-case-3",
+				Dir:  "testdata/suite/multiple-cases/test-case-3",
+			},
+		}, cases)
+
+		// excluded test cases are still discovered
+		require.Len(t, suite.Cases(t), 3)
+	})
+
 	t.Run("only", func(t *testing.T) {
 		var mt mockT
 		var cases []TestCase
@@ -195,6 +392,62 @@ func TestTestSuite(t *testing.T) {
 		}, mt)
 	})
 
+	t.Run("input and output dirs", func(t *testing.T) {
+		var mt mockT
+		var cases []TestCase
+
+		suite := TestSuite{
+			Dir:       "testdata/suite/split",
+			InputDir:  "in",
+			OutputDir: "out",
+			TestFunc: func(t *testing.T, tc TestCase) {
+				t.Helper()
+
+				cases = append(cases, tc)
+
+				type Test struct {
+					Input string `testdata:"input.txt"`
+				}
+
+				type Expected struct {
+					Output string `testdata:"expected.txt"`
+				}
+
+				var test Test
+				tc.Load(&mt, &test)
+
+				tc.Assert(&mt, &Expected{Output: strings.ToUpper(test.Input)})
+			},
+		}
+
+		suite.Run(t)
+
+		require.ElementsMatch(t, []TestCase{
+			{
+				Name:      "test-case-1",
+				Dir:       "testdata/suite/split/test-case-1",
+				InputDir:  "in",
+				OutputDir: "out",
+			},
+			{
+				Name:      "test-case-2",
+				Dir:       "testdata/suite/split/test-case-2",
+				InputDir:  "in",
+				OutputDir: "out",
+			},
+		}, cases)
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			logs: []string{
+				`[GoT] Load: *got.Test.Input: loaded file "testdata/suite/split/test-case-1/in/input.txt" as string (size 11)`,
+				`[GoT] Assert: *got.Expected.Output: loaded file "testdata/suite/split/test-case-1/out/expected.txt" as string (size 11)`,
+				`[GoT] Load: *got.Test.Input: loaded file "testdata/suite/split/test-case-2/in/input.txt" as string (size 7)`,
+				`[GoT] Assert: *got.Expected.Output: loaded file "testdata/suite/split/test-case-2/out/expected.txt" as string (size 7)`,
+			},
+		}, mt)
+	})
+
 	t.Run("shared dir", func(t *testing.T) {
 		var mt mockT
 		var cases []TestCase
@@ -356,3 +609,528 @@ func TestTestSuite(t *testing.T) {
 		}, mt)
 	})
 }
+
+func TestCompareTestSuite(t *testing.T) {
+	type Test struct {
+		Input string `testdata:"input.txt"`
+	}
+
+	suite := TestSuite{Dir: "testdata/suite/multiple-cases"}
+
+	upper := func(t *testing.T, tc TestCase) string {
+		t.Helper()
+
+		var test Test
+		tc.Load(t, &test)
+
+		return strings.ToUpper(test.Input)
+	}
+
+	upperBytes := func(t *testing.T, tc TestCase) string {
+		t.Helper()
+
+		var test Test
+		tc.Load(t, &test)
+
+		return string(bytes.ToUpper([]byte(test.Input)))
+	}
+
+	CompareTestSuite(t, &suite, upper, upperBytes)
+
+	t.Run("parallel", func(t *testing.T) {
+		// a is called first, so it is the one to mark each test case parallel
+		parallel := func(t *testing.T, tc TestCase) string {
+			t.Parallel()
+			return upper(t, tc)
+		}
+
+		t.Run("match", func(t *testing.T) {
+			CompareTestSuite(t, &suite, parallel, upperBytes)
+		})
+
+		ft := &fatalT{}
+		t.Run("diverge", func(t *testing.T) {
+			ft.T = t
+			CompareTestSuite(ft, &suite, parallel, func(t *testing.T, tc TestCase) string {
+				return tc.Name
+			})
+		})
+
+		require.Len(t, ft.fatals, 1)
+		require.Contains(t, ft.fatals[0], `test case "test-case-1" outputs differ`)
+		require.Contains(t, ft.fatals[0], `test case "test-case-3" outputs differ`)
+	})
+}
+
+// fatalT records calls to Fatalf rather than failing the test, which allows
+// checking failures reported after parallel subtests have completed.
+type fatalT struct {
+	*testing.T
+	fatals []string
+}
+
+func (t *fatalT) Fatalf(msg string, args ...any) {
+	t.fatals = append(t.fatals, fmt.Sprintf(msg, args...))
+}
+
+func TestAssertEqualOutputs(t *testing.T) {
+	t.Run("match", func(t *testing.T) {
+		var mt mockT
+		AssertEqualOutputs(&mt,
+			map[string]string{"test-case-1": "HELLO", "test-case-2": "WORLD"},
+			map[string]string{"test-case-1": "HELLO", "test-case-2": "WORLD"},
+		)
+
+		require.EqualValues(t, mockT{helper: true}, mt)
+	})
+
+	t.Run("diverge", func(t *testing.T) {
+		var mt mockT
+		AssertEqualOutputs(&mt,
+			map[string]string{"test-case-1": "HELLO", "test-case-2": "WORLD", "test-case-3": "A"},
+			map[string]string{"test-case-1": "HELLO", "test-case-2": "world", "test-case-4": "B"},
+		)
+
+		require.True(t, mt.failed)
+		require.Len(t, mt.logs, 1)
+
+		lines := strings.Split(mt.logs[0], "\n")
+		require.True(t, strings.HasPrefix(lines[0], `[GoT] AssertEqualOutputs: test case "test-case-2" outputs differ:`), lines[0])
+		require.Contains(t, mt.logs[0], `test case "test-case-3" is missing from b`)
+		require.Contains(t, mt.logs[0], `test case "test-case-4" is missing from a`)
+		require.NotContains(t, mt.logs[0], `"test-case-1"`)
+	})
+
+	t.Run("unexported fields", func(t *testing.T) {
+		type output struct {
+			Value    string
+			internal int
+		}
+
+		var mt mockT
+		AssertEqualOutputs(&mt,
+			map[string]output{"test-case-1": {Value: "HELLO", internal: 1}},
+			map[string]output{"test-case-1": {Value: "HELLO", internal: 2}},
+		)
+
+		require.EqualValues(t, mockT{helper: true}, mt)
+	})
+}
+
+func TestTestSuiteCases(t *testing.T) {
+	suite := TestSuite{Dir: "testdata/suite/skip"}
+
+	require.Equal(t, []TestCase{
+		{Name: "test-case-1", Dir: "testdata/suite/skip/test-case-1"},
+		{Name: "test-case-2", Skip: true, Dir: "testdata/suite/skip/test-case-2.skip"},
+		{Name: "test-case-3", Dir: "testdata/suite/skip/test-case-3"},
+	}, suite.Cases(t))
+}
+
+func TestTestSuiteConflicts(t *testing.T) {
+	t.Run("skip and only", func(t *testing.T) {
+		var mt mockT
+		suite := TestSuite{Dir: "testdata/suite/conflict-both"}
+
+		require.Empty(t, suite.Cases(&mt))
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"test case testdata/suite/conflict-both/test-case-1.only.skip cannot be marked as both skip and only",
+			},
+		}, mt)
+	})
+
+	t.Run("duplicate names", func(t *testing.T) {
+		var mt mockT
+		suite := TestSuite{Dir: "testdata/suite/conflict-dupe"}
+
+		require.Empty(t, suite.Cases(&mt))
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"test case test-case-1 is defined more than once: testdata/suite/conflict-dupe/test-case-1.only and testdata/suite/conflict-dupe/test-case-1.skip",
+			},
+		}, mt)
+	})
+}
+
+func TestTestSuiteIndexFile(t *testing.T) {
+	t.Run("selects and orders cases", func(t *testing.T) {
+		suite := TestSuite{Dir: "testdata/suite/index", IndexFile: "index.txt"}
+
+		require.Equal(t, []TestCase{
+			{Name: "test-case-3", Dir: "testdata/suite/index/test-case-3"},
+			{Name: "test-case-1", Dir: "testdata/suite/index/test-case-1"},
+		}, suite.Cases(t))
+	})
+
+	t.Run("unknown case", func(t *testing.T) {
+		var mt mockT
+		suite := TestSuite{Dir: "testdata/suite/index", IndexFile: "unknown.txt"}
+
+		require.Empty(t, suite.Cases(&mt))
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"index file testdata/suite/index/unknown.txt lists unknown test case test-case-4",
+			},
+		}, mt)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		var mt mockT
+		suite := TestSuite{Dir: "testdata/suite/index", IndexFile: "missing.txt"}
+
+		require.Empty(t, suite.Cases(&mt))
+		require.True(t, mt.failed)
+	})
+}
+
+func TestTestSuiteDirs(t *testing.T) {
+	t.Run("multiple roots", func(t *testing.T) {
+		suite := TestSuite{Dirs: []string{"testdata/suite/roots/unit", "testdata/suite/roots/integration"}}
+
+		require.Equal(t, []TestCase{
+			{Name: "integration-1", Dir: "testdata/suite/roots/integration/integration-1"},
+			{Name: "unit-1", Dir: "testdata/suite/roots/unit/unit-1"},
+			{Name: "unit-2", Dir: "testdata/suite/roots/unit/unit-2"},
+		}, suite.Cases(t))
+	})
+
+	t.Run("with dir", func(t *testing.T) {
+		suite := TestSuite{Dir: "testdata/suite/roots/unit", Dirs: []string{"testdata/suite/roots/integration"}}
+
+		require.Len(t, suite.Cases(t), 3)
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		var mt mockT
+		suite := TestSuite{Dirs: []string{"testdata/suite/roots/unit", "testdata/suite/roots/collision"}}
+
+		require.Empty(t, suite.Cases(&mt))
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"test case unit-1 is defined more than once: testdata/suite/roots/unit/unit-1 and testdata/suite/roots/collision/unit-1",
+			},
+		}, mt)
+	})
+}
+
+func TestTestSuiteGlob(t *testing.T) {
+	t.Run("multiple matches", func(t *testing.T) {
+		suite := TestSuite{Dir: "testdata/suite/glob/*/cases"}
+
+		require.Equal(t, []TestCase{
+			{Name: "login", Dir: "testdata/suite/glob/feature-a/cases/login"},
+			{Name: "logout", Dir: "testdata/suite/glob/feature-a/cases/logout"},
+			{Name: "search", Dir: "testdata/suite/glob/feature-b/cases/search"},
+		}, suite.Cases(t))
+	})
+
+	t.Run("with dirs", func(t *testing.T) {
+		suite := TestSuite{Dirs: []string{"testdata/suite/glob/feature-*/cases", "testdata/suite/roots/unit"}}
+
+		require.Len(t, suite.Cases(t), 5)
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		var mt mockT
+		suite := TestSuite{Dir: "testdata/suite/glob-collision/*/cases"}
+
+		require.Empty(t, suite.Cases(&mt))
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"test case same is defined more than once: testdata/suite/glob-collision/a/cases/same and testdata/suite/glob-collision/b/cases/same",
+			},
+		}, mt)
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		var mt mockT
+		suite := TestSuite{Dir: "testdata/suite/glob/*/missing"}
+
+		require.Empty(t, suite.Cases(&mt))
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"dir pattern testdata/suite/glob/*/missing does not match any directories",
+			},
+		}, mt)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		var mt mockT
+		suite := TestSuite{Dir: "testdata/suite/glob/[/cases"}
+
+		require.Empty(t, suite.Cases(&mt))
+		require.True(t, mt.failed)
+	})
+}
+
+// runT is a mockT which runs sub-tests for real, so the results of a suite
+// can be checked without failing the parent test.
+type runT struct {
+	mockT
+	t *testing.T
+}
+
+func (r *runT) Run(name string, fn func(t *testing.T)) bool {
+	return r.t.Run(name, fn)
+}
+
+func TestTestSuiteRunGuards(t *testing.T) {
+	noop := func(t *testing.T, tc TestCase) {}
+
+	t.Run("require all run", func(t *testing.T) {
+		rt := runT{t: t}
+		suite := TestSuite{Dir: "testdata/suite/skip", RequireAllRun: true, TestFunc: noop}
+		suite.Run(&rt)
+
+		require.True(t, rt.failed)
+		require.Equal(t, []string{
+			"[GoT] TestSuite: only 2 of 3 test cases were run, but all are required",
+		}, rt.logs)
+	})
+
+	t.Run("min cases", func(t *testing.T) {
+		rt := runT{t: t}
+		suite := TestSuite{Dir: "testdata/suite/skip", MinCases: 3, TestFunc: noop}
+		suite.Run(&rt)
+
+		require.True(t, rt.failed)
+		require.Equal(t, []string{
+			"[GoT] TestSuite: only 2 of 3 test cases were run, but at least 3 are required",
+		}, rt.logs)
+	})
+
+	t.Run("satisfied", func(t *testing.T) {
+		rt := runT{t: t}
+		suite := TestSuite{Dir: "testdata/suite/multiple-cases", MinCases: 3, RequireAllRun: true, TestFunc: noop}
+		suite.Run(&rt)
+
+		require.False(t, rt.failed, rt.logs)
+	})
+}
+
+func TestTestSuiteGoldenDir(t *testing.T) {
+	type input struct {
+		Input string `testdata:"input.txt"`
+	}
+
+	type output struct {
+		Output string `testdata:"output.txt"`
+	}
+
+	t.Run("cases", func(t *testing.T) {
+		suite := TestSuite{Dir: "testdata/suite/golden/cases", GoldenDir: "testdata/suite/golden/expected"}
+
+		require.Equal(t, []TestCase{
+			{Name: "case-1", Dir: "testdata/suite/golden/cases/case-1", GoldenDir: "testdata/suite/golden/expected/case-1"},
+			{Name: "case-2", Skip: true, Dir: "testdata/suite/golden/cases/case-2.skip", GoldenDir: "testdata/suite/golden/expected/case-2"},
+		}, suite.Cases(t))
+	})
+
+	t.Run("assert", func(t *testing.T) {
+		suite := TestSuite{Dir: "testdata/suite/golden/cases", GoldenDir: "testdata/suite/golden/expected"}
+
+		for _, tc := range suite.Cases(t) {
+			var in input
+			tc.Load(t, &in)
+
+			var mt mockT
+			tc.Assert(&mt, &output{Output: strings.ToUpper(in.Input)})
+			require.False(t, mt.failed, mt.logs)
+
+			mt = mockT{}
+			tc.Assert(&mt, &output{Output: in.Input})
+			require.True(t, mt.failed)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		golden := t.TempDir()
+		suite := TestSuite{Dir: "testdata/suite/golden/cases", GoldenDir: golden}
+
+		for _, tc := range suite.Cases(t) {
+			var mt mockT
+			tc.Assert(&mt, &output{Output: "updated " + tc.Name})
+			require.False(t, mt.failed, mt.logs)
+
+			data, err := os.ReadFile(filepath.Join(golden, tc.Name, "output.txt"))
+			require.NoError(t, err)
+			require.Equal(t, "updated "+tc.Name, string(data))
+		}
+
+		_, err := os.Stat("testdata/suite/golden/cases/case-1/output.txt")
+		require.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestTestSuiteRetries(t *testing.T) {
+	tc := TestCase{Name: "flaky", Dir: "testdata/suite/multiple-cases/test-case-1"}
+
+	t.Run("fails once then passes", func(t *testing.T) {
+		var calls int
+		suite := TestSuite{
+			Retries: 2,
+			RetryFunc: func(t *Attempt, tc TestCase) {
+				calls++
+				if calls == 1 {
+					t.Fatalf("attempt %d failed", calls)
+				}
+				t.Logf("attempt %d passed", calls)
+			},
+		}
+
+		var mt mockT
+		suite.runAttempts(&mt, tc)
+
+		require.Equal(t, 2, calls)
+		require.EqualValues(t, mockT{
+			helper: true,
+			logs: []string{
+				"[GoT] TestSuite: attempt 1 of 3 failed:\nattempt 1 failed",
+				"attempt 2 passed",
+				"[GoT] TestSuite: passed on attempt 2 of 3",
+			},
+		}, mt)
+	})
+
+	t.Run("always fails", func(t *testing.T) {
+		var calls int
+		suite := TestSuite{
+			Retries: 1,
+			RetryFunc: func(t *Attempt, tc TestCase) {
+				calls++
+				t.Errorf("attempt %d failed", calls)
+				t.Log("still running")
+			},
+		}
+
+		var mt mockT
+		suite.runAttempts(&mt, tc)
+
+		require.Equal(t, 2, calls)
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"[GoT] TestSuite: attempt 1 of 2 failed:\nattempt 1 failed\nstill running",
+				"[GoT] TestSuite: attempt 2 of 2 failed:\nattempt 2 failed\nstill running",
+				"[GoT] TestSuite: all 2 attempts failed",
+			},
+		}, mt)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		suite := TestSuite{
+			RetryFunc: func(t *Attempt, tc TestCase) {
+				panic("boom")
+			},
+		}
+
+		var mt mockT
+		suite.runAttempts(&mt, tc)
+
+		require.True(t, mt.failed)
+		require.Equal(t, "[GoT] TestSuite: attempt 1 of 1 failed:\npanic: boom", mt.logs[0])
+	})
+
+	t.Run("load and assert", func(t *testing.T) {
+		var calls int
+		suite := TestSuite{
+			Dir:     "testdata/suite/multiple-cases",
+			Retries: 1,
+			RetryFunc: func(t *Attempt, tc TestCase) {
+				calls++
+
+				var input struct {
+					Input string `testdata:"input.txt"`
+				}
+				tc.Load(t, &input)
+
+				if calls%2 == 1 {
+					tc.Assert(t, &struct {
+						Input string `testdata:"input.txt"`
+					}{Input: "wrong"})
+				}
+			},
+		}
+
+		rt := runT{t: t}
+		suite.Run(&rt)
+
+		require.False(t, rt.failed, rt.logs)
+		require.Equal(t, 6, calls)
+	})
+
+	t.Run("requires retry func", func(t *testing.T) {
+		var mt mockT
+		suite := TestSuite{Dir: "testdata/suite/multiple-cases", Retries: 1, TestFunc: func(t *testing.T, tc TestCase) {}}
+		suite.Run(&mt)
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"[GoT] TestSuite: Retries requires RetryFunc, since TestFunc cannot be retried",
+			},
+		}, mt)
+	})
+}
+
+func TestTestSuiteWorkDir(t *testing.T) {
+	var mu sync.Mutex
+	dirs := make(map[string]bool)
+
+	t.Run("parallel", func(t *testing.T) {
+		suite := TestSuite{
+			Dir:           "testdata/suite/multiple-cases",
+			CreateWorkDir: true,
+			TestFunc: func(t *testing.T, tc TestCase) {
+				t.Parallel()
+
+				entries, err := os.ReadDir(tc.WorkDir)
+				require.NoError(t, err)
+				require.Empty(t, entries)
+
+				require.NoError(t, os.WriteFile(filepath.Join(tc.WorkDir, "output.txt"), []byte(tc.Name), 0644))
+
+				mu.Lock()
+				defer mu.Unlock()
+				dirs[tc.WorkDir] = true
+			},
+		}
+
+		suite.Run(&runT{t: t})
+	})
+
+	require.Len(t, dirs, 3)
+	for dir := range dirs {
+		_, err := os.Stat(dir)
+		require.True(t, os.IsNotExist(err), "%s was not removed", dir)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		suite := TestSuite{
+			Dir: "testdata/suite/multiple-cases",
+			TestFunc: func(t *testing.T, tc TestCase) {
+				require.Empty(t, tc.WorkDir)
+			},
+		}
+
+		suite.Run(&runT{t: t})
+	})
+}
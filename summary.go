@@ -0,0 +1,78 @@
+package got
+
+import "sync"
+
+// SummaryCounts are the aggregate results of every TestSuite and Assert in
+// the test binary, as returned by Summary.
+type SummaryCounts struct {
+	// Cases is the number of test cases found by TestSuite.Run.
+	Cases int
+
+	// Ran is the number of test cases which were run (ie: not skipped).
+	Ran int
+
+	// Passed is the number of test cases which were run without failing.
+	Passed int
+
+	// Failed is the number of test cases which were run and failed.
+	Failed int
+
+	// Skipped is the number of test cases which were skipped, whether by Skip,
+	// Only, When or the test itself.
+	Skipped int
+
+	// Updated is the number of values saved by Assert while updating golden
+	// files with "-update-golden".
+	Updated int
+}
+
+var summary struct {
+	sync.Mutex
+	counts SummaryCounts
+}
+
+// Summary returns the aggregate results of every TestSuite and Assert run so
+// far in the test binary, which is useful for reporting (eg: a dashboard) from
+// TestMain after m.Run returns. The counts start at zero for each run of the
+// test binary.
+func Summary() SummaryCounts {
+	summary.Lock()
+	defer summary.Unlock()
+
+	return summary.counts
+}
+
+// ResetSummary sets all of the counts returned by Summary back to zero.
+func ResetSummary() {
+	summary.Lock()
+	defer summary.Unlock()
+
+	summary.counts = SummaryCounts{}
+}
+
+// recordSummary updates the counts returned by Summary using fn.
+func recordSummary(fn func(c *SummaryCounts)) {
+	summary.Lock()
+	defer summary.Unlock()
+
+	fn(&summary.counts)
+}
+
+// recordCase updates the counts returned by Summary for a test case which has
+// finished running.
+func recordCase(skipped, failed bool) {
+	recordSummary(func(c *SummaryCounts) {
+		c.Cases++
+
+		switch {
+		case skipped:
+			c.Skipped++
+		case failed:
+			c.Ran++
+			c.Failed++
+		default:
+			c.Ran++
+			c.Passed++
+		}
+	})
+}
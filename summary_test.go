@@ -0,0 +1,59 @@
+package got
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	ResetSummary()
+	t.Cleanup(ResetSummary)
+
+	type output struct {
+		Name string `testdata:"name.txt"`
+	}
+
+	t.Run("suites", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		first := TestSuite{
+			Dir:       "testdata/suite/multiple-cases",
+			GoldenDir: t.TempDir(),
+			TestFunc: func(t *testing.T, tc TestCase) {
+				tc.Assert(t, &output{Name: tc.Name})
+			},
+		}
+		first.Run(&runT{t: t})
+
+		second := TestSuite{
+			Dir:      "testdata/suite/skip",
+			TestFunc: func(t *testing.T, tc TestCase) {},
+		}
+		second.Run(&runT{t: t})
+
+		require.Equal(t, SummaryCounts{
+			Cases:   6,
+			Ran:     5,
+			Passed:  5,
+			Skipped: 1,
+			Updated: 3,
+		}, Summary())
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		ResetSummary()
+
+		recordCase(false, true)
+		recordCase(true, false)
+
+		require.Equal(t, SummaryCounts{Cases: 2, Ran: 1, Failed: 1, Skipped: 1}, Summary())
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		ResetSummary()
+
+		require.Equal(t, SummaryCounts{}, Summary())
+	})
+}
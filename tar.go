@@ -0,0 +1,295 @@
+package got
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LoadTar is the same as Load, but the input directory is backed by a tar
+// archive rather than a directory on disk. Archives with a ".tgz" or ".tar.gz"
+// extension are decompressed with gzip on the fly.
+//
+// The members are read into memory (never extracted to disk), and are named
+// after the archive in logs and errors (eg: "fixtures.tar:/input.json"). This
+// is useful for very large sets of fixtures which would otherwise add
+// thousands of files to a repository.
+func LoadTar(t tester, file string, values ...any) {
+	t.Helper()
+
+	log := &logger{
+		t:      t,
+		prefix: "[GoT] Load: ",
+	}
+
+	if err := loadTar(log, file, values...); err != nil {
+		t.Fatalf("[GoT] LoadTar: %s", err.Error())
+	}
+}
+
+func loadTar(log *logger, file string, values ...any) error {
+	fsys, err := readTarFile(file)
+	if err != nil {
+		return err
+	}
+
+	return Options{fsys: fsys}.loadDirs(log, []string{fsys.root}, values...)
+}
+
+// readTarFile reads the archive file, which is decompressed first when it has a
+// gzip extension.
+func readTarFile(file string) (*archiveFS, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive %s: %w", file, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if isTarGz(file) {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress archive %s: %w", file, err)
+		}
+		defer gz.Close()
+
+		r = gz
+	}
+
+	fsys, err := readArchive(file+":", r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", file, err)
+	}
+
+	return fsys, nil
+}
+
+func isTarGz(file string) bool {
+	return strings.HasSuffix(file, ".tgz") || strings.HasSuffix(file, ".tar.gz")
+}
+
+// The limits for reading an archive into memory, which guard against archives
+// that would otherwise exhaust it (eg: a gzip bomb).
+var (
+	maxArchiveEntries       = 100000
+	maxArchiveSize    int64 = 1 << 30
+)
+
+// archiveFS is a read-only fileSystem holding the members of an archive in
+// memory, where each member is named by joining root (eg: "fixtures.tar:")
+// with its path within the archive.
+type archiveFS struct {
+	root  string
+	files map[string][]byte
+	dirs  map[string]map[string]bool
+}
+
+// readArchive reads each member of the tar archive in r into memory.
+func readArchive(root string, r io.Reader) (*archiveFS, error) {
+	a := &archiveFS{
+		root:  root,
+		files: make(map[string][]byte),
+		dirs:  map[string]map[string]bool{".": {}},
+	}
+
+	tr := tar.NewReader(r)
+
+	var entries int
+	var size int64
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return a, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if entries++; entries > maxArchiveEntries {
+			return nil, fmt.Errorf("archive exceeds max entries of %d", maxArchiveEntries)
+		}
+
+		name := path.Clean(header.Name)
+		if !fs.ValidPath(name) {
+			return nil, fmt.Errorf("invalid file path %q", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			a.addDir(name)
+		case tar.TypeReg:
+			if size += header.Size; size > maxArchiveSize {
+				return nil, fmt.Errorf("archive exceeds max size of %d bytes", maxArchiveSize)
+			}
+
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", a.name(name), err)
+			}
+
+			a.files[name] = data
+			a.addDir(path.Dir(name))
+			a.dirs[path.Dir(name)][path.Base(name)] = true
+		}
+	}
+}
+
+// addDir adds dir (and its parents) to a.
+func (a *archiveFS) addDir(dir string) {
+	for dir != "." {
+		if _, ok := a.dirs[dir]; !ok {
+			a.dirs[dir] = make(map[string]bool)
+		}
+
+		parent := path.Dir(dir)
+		if _, ok := a.dirs[parent]; !ok {
+			a.dirs[parent] = make(map[string]bool)
+		}
+		a.dirs[parent][path.Base(dir)] = true
+
+		dir = parent
+	}
+}
+
+// name returns the name of member used in logs and errors.
+func (a *archiveFS) name(member string) string {
+	return filepath.Join(a.root, filepath.FromSlash(member))
+}
+
+// member returns the path of the member for name (see archiveFS.name), which
+// is "." for the root.
+func (a *archiveFS) member(name string) (string, bool) {
+	if !strings.HasPrefix(name, a.root) {
+		return "", false
+	}
+
+	rest := name[len(a.root):]
+	if rest != "" && !os.IsPathSeparator(rest[0]) {
+		return "", false
+	}
+
+	member := strings.TrimLeft(filepath.ToSlash(rest), "/")
+	if member == "" {
+		return ".", true
+	}
+
+	return path.Clean(member), true
+}
+
+func (a *archiveFS) Open(name string) (fs.File, error) {
+	info, err := a.Stat(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	member, _ := a.member(name)
+	return &archiveFile{info: info.(*archiveInfo), r: bytes.NewReader(a.files[member])}, nil
+}
+
+func (a *archiveFS) Stat(name string) (fs.FileInfo, error) {
+	member, ok := a.member(name)
+	if ok {
+		if data, ok := a.files[member]; ok {
+			return &archiveInfo{name: path.Base(member), size: int64(len(data))}, nil
+		}
+
+		if _, ok := a.dirs[member]; ok {
+			return &archiveInfo{name: path.Base(member), dir: true}, nil
+		}
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (a *archiveFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	member, ok := a.member(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	children, ok := a.dirs[member]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	names := make([]string, 0, len(children))
+	for child := range children {
+		names = append(names, child)
+	}
+	sort.Strings(names)
+
+	entries := make([]fs.DirEntry, len(names))
+	for i, child := range names {
+		info, err := a.Stat(a.name(path.Join(member, child)))
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+
+	return entries, nil
+}
+
+// archiveFile is an open member of an archiveFS.
+type archiveFile struct {
+	info *archiveInfo
+	r    *bytes.Reader
+}
+
+func (f *archiveFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *archiveFile) Read(p []byte) (int, error) {
+	if f.info.dir {
+		return 0, &fs.PathError{Op: "read", Path: f.info.name, Err: errors.New("is a directory")}
+	}
+	return f.r.Read(p)
+}
+
+func (f *archiveFile) Close() error {
+	return nil
+}
+
+// archiveInfo is the fs.FileInfo for a member of an archiveFS.
+type archiveInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i *archiveInfo) Name() string {
+	return i.name
+}
+
+func (i *archiveInfo) Size() int64 {
+	return i.size
+}
+
+func (i *archiveInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (i *archiveInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (i *archiveInfo) IsDir() bool {
+	return i.dir
+}
+
+func (i *archiveInfo) Sys() any {
+	return nil
+}
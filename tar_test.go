@@ -0,0 +1,152 @@
+package got
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTar(t *testing.T) {
+	type test struct {
+		Input    map[string]string `testdata:"input.json"`
+		Expected map[string]string `testdata:"expected/*.txt,explode"`
+	}
+
+	files := map[string]string{
+		"input.json":     `{"a": "hello", "b": "world"}`,
+		"expected/a.txt": "HELLO",
+		"expected/b.txt": "WORLD",
+	}
+
+	expected := test{
+		Input:    map[string]string{"a": "hello", "b": "world"},
+		Expected: map[string]string{"expected/a.txt": "HELLO", "expected/b.txt": "WORLD"},
+	}
+
+	t.Run("tar", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "fixtures.tar")
+		require.NoError(t, os.WriteFile(file, makeTar(t, files), 0644))
+
+		var mt mockT
+		var actual test
+		LoadTar(&mt, file, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.EqualValues(t, expected, actual)
+	})
+
+	for _, ext := range []string{".tgz", ".tar.gz"} {
+		t.Run(ext, func(t *testing.T) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			_, err := gz.Write(makeTar(t, files))
+			require.NoError(t, err)
+			require.NoError(t, gz.Close())
+
+			file := filepath.Join(t.TempDir(), "fixtures"+ext)
+			require.NoError(t, os.WriteFile(file, buf.Bytes(), 0644))
+
+			var mt mockT
+			var actual test
+			LoadTar(&mt, file, &actual)
+
+			require.False(t, mt.failed, mt.logs)
+			require.EqualValues(t, expected, actual)
+		})
+	}
+
+	t.Run("logs member names", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "fixtures.tar")
+		require.NoError(t, os.WriteFile(file, makeTar(t, files), 0644))
+
+		var mt mockT
+		LoadTar(&mt, file, new(test))
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, []string{
+			`[GoT] Load: *got.test.Input: loaded file "` + file + `:/input.json" as JSON (size 28)`,
+			`[GoT] Load: *got.test.Expected["expected/a.txt"]: loaded file "` + file + `:/expected/a.txt" as string (size 5)`,
+			`[GoT] Load: *got.test.Expected["expected/b.txt"]: loaded file "` + file + `:/expected/b.txt" as string (size 5)`,
+		}, mt.logs)
+	})
+
+	t.Run("max entries", func(t *testing.T) {
+		prev := maxArchiveEntries
+		maxArchiveEntries = 2
+		t.Cleanup(func() { maxArchiveEntries = prev })
+
+		file := filepath.Join(t.TempDir(), "fixtures.tar")
+		require.NoError(t, os.WriteFile(file, makeTar(t, files), 0644))
+
+		var mt mockT
+		LoadTar(&mt, file, new(test))
+
+		require.True(t, mt.failed)
+		require.Equal(t, []string{"[GoT] LoadTar: failed to read archive " + file + ": archive exceeds max entries of 2"}, mt.logs)
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		prev := maxArchiveSize
+		maxArchiveSize = 10
+		t.Cleanup(func() { maxArchiveSize = prev })
+
+		file := filepath.Join(t.TempDir(), "fixtures.tar")
+		require.NoError(t, os.WriteFile(file, makeTar(t, files), 0644))
+
+		var mt mockT
+		LoadTar(&mt, file, new(test))
+
+		require.True(t, mt.failed)
+		require.Equal(t, []string{"[GoT] LoadTar: failed to read archive " + file + ": archive exceeds max size of 10 bytes"}, mt.logs)
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "fixtures.tar")
+		require.NoError(t, os.WriteFile(file, makeTar(t, map[string]string{"../input.json": "{}"}), 0644))
+
+		var mt mockT
+		LoadTar(&mt, file, new(test))
+
+		require.True(t, mt.failed)
+		require.Len(t, mt.logs, 1)
+		require.True(t, strings.HasSuffix(mt.logs[0], `invalid file path "../input.json"`), mt.logs[0])
+	})
+
+	t.Run("missing archive", func(t *testing.T) {
+		var mt mockT
+		LoadTar(&mt, "testdata/missing.tgz", new(test))
+
+		require.True(t, mt.failed)
+		require.Len(t, mt.logs, 1)
+		require.True(t, strings.HasPrefix(mt.logs[0], "[GoT] LoadTar: failed to open archive testdata/missing.tgz"), mt.logs[0])
+	})
+}
+
+func makeTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(contents)),
+			Typeflag: tar.TypeReg,
+		}))
+
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+
+	return buf.Bytes()
+}
@@ -1,19 +1,25 @@
 package got
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dominicbarnes/got/v2/codec"
 	"github.com/fatih/structtag"
 	"github.com/google/go-cmp/cmp"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
 var updateGolden bool
@@ -22,8 +28,19 @@ func init() {
 	flag.BoolVar(&updateGolden, "update-golden", false, "instruct got.Assert to update golden files")
 }
 
+// SetUpdateGolden programmatically toggles the same behavior as the
+// "update-golden" flag, which is useful when only specific tests should be
+// updating golden files.
+func SetUpdateGolden(update bool) {
+	updateGolden = update
+}
+
 const tagName = "testdata"
 
+// lockEnv is the environment variable which prevents golden files from being
+// updated, to protect against "-update-golden" masking regressions in CI.
+const lockEnv = "GOT_NO_UPDATE"
+
 // Load extracts the contents of dir into values which are structs annotated
 // with the "testdata" struct tag.
 //
@@ -35,42 +52,143 @@ const tagName = "testdata"
 //
 // Struct values will be decoded using the file extension to map to a [Codec].
 // For example, ".json" files can be processed using [JSONCodec] if it has been
-// registered. Additional codecs (eg: YAML, TOML) can be registered if desired.
+// registered. Additional codecs (eg: XML) can be registered if desired.
+// When the extension has no registered codec, the leading bytes of the file
+// are checked against the signatures registered with [codec.RegisterMagic].
+//
+// Bool and number fields (or pointers to them) are parsed from plain text when
+// the file extension has no registered codec (eg: "count.txt"), where pointers
+// are left nil when the file is missing or empty. Saving a nil pointer to a
+// scalar writes an empty file, which loads back as nil.
 //
-// Map values, by default, are decoded using the relevant [Codec].
+// The "codec" option uses a registered codec by name regardless of the file
+// extension (eg: `testdata:"data.txt,codec=json"` for a ".txt" file which
+// contains JSON), which takes precedence over both the extension and
+// Options.CodecResolver. The same codec is used when saving golden files.
+//
+// Map values, by default, are decoded using the relevant [Codec], which means
+// any key type supported by that codec can be used (eg: map[int]string).
 //
 // There is also a special mode that works files more dynamically, which is
 // useful for highly variable outputs and is enabled with the "explode" option.
 // When enabled, the struct tag name is treated as a glob pattern. The map is
 // populated with a key corresponding to a relative filepath while the value can
-// be any of the types described above.
+// be any of the types described above. A "**" path segment matches any number
+// of directories (eg: `testdata:"requests/**/*.json,explode"`), while a pattern
+// ending in "**" will capture every file under that directory recursively, with
+// the "exclude" option used to omit specific files (eg:
+// `testdata:"**,explode,exclude=*.log|input.json"`).
+// The "strip" option can be used to trim a common directory from each of the
+// keys, such as in `testdata:"expected/*.txt,explode,strip=expected/"`, where
+// every match must be within that directory. When there
+// are no matching files, the map is left nil unless the "empty" option is used
+// to initialize an empty map instead (eg: `testdata:"*.txt,explode,empty"`).
+//
+// The "explode" option also supports slices of raw values (ie: [][]byte or
+// []string), which hold the contents of each matching file in sorted filename
+// order (eg: `testdata:"frames/*.bin,explode"`). These are saved to files named
+// by replacing the "*" in the pattern with the index of each element.
+//
+// The same map type (eg: map[string]Request) can be used in either mode, so the
+// "explode" option alone determines the behavior: without it, a single file is
+// decoded into the entire map (eg: a JSON object keyed by name), and with it,
+// each matching file is decoded into a single entry keyed by its path.
+//
+// The "{goos}" and "{goarch}" placeholders are replaced with the current
+// platform, so `testdata:"expected_{goos}.txt"` loads "expected_linux.txt" on
+// Linux, falling back to "expected.txt" when the platform-specific file is
+// missing. Saving writes to whichever of these files would be loaded, so a
+// platform-specific golden file is only updated when it already exists.
+//
+// The "schema" option validates a file against a JSON Schema (a path relative
+// to the input directory) before it is decoded, so malformed fixtures are
+// reported clearly (eg: `testdata:"input.yaml,schema=input.schema.json"`).
+//
+// Files with a YAML front matter block (eg: markdown) can be split across 2
+// fields using the "frontmatter" option for the header (always decoded as
+// YAML) and the "body" option for the remaining raw contents.
+//
+// Multiple fields can be combined into a single file (eg: a report) using the
+// "concat" option, where the value determines the order of each section within
+// the file (eg: `testdata:"report.txt,concat=1"`). The sections are separated
+// by a line of "=" characters.
+//
+// The "nonempty" option fails when a file exists but has no contents, which
+// guards against an accidentally empty fixture making a test pass vacuously
+// (eg: `testdata:"input.json,nonempty"`). Missing files are still skipped.
+//
+// The "limit" option keeps only the first N elements when loading a slice,
+// which is useful for tests that only need a sample of a large array (eg:
+// `testdata:"items.json,limit=5"`). Since the limit only applies when loading,
+// it is intended for inputs rather than values passed to Assert.
+//
+// The "resolvepaths" option resolves relative file paths within the loaded
+// strings against the input directory, such as for a config which references
+// another fixture (eg: `testdata:"paths.json,resolvepaths"`).
+//
+// The "gzip", "expand", "trim" and "text" options transform the raw contents
+// of a file before it is decoded, which are always applied in that order (eg:
+// `testdata:"data.json.gz,gzip,expand"` decompresses, then expands environment
+// variables, then decodes as JSON). The "text" option gives text files (but
+// not binary ones) a trailing newline when saved, which is removed on load.
+//
+// The "include" option inlines other JSON files into a file before it is
+// decoded, where {"$include": "shared/a.json"} is replaced with the contents of
+// that file (relative to the input directory).
+//
+// The file name can list alternatives separated by "|", where the first file
+// which exists is loaded using the codec for its own extension (eg:
+// `testdata:"input.json|input.yaml"`).
+//
+// The "desc" option describes what a file represents, which is included in
+// logs and errors for that field (eg: `testdata:"input.json,desc=login"`).
+//
+// An optional "manifest.json" within dir can map field names to different files
+// than the struct tags specify (eg: {"Input": "alternate.json"}), which allows
+// individual test cases to use differently named files. Each name must be a
+// field of at least one of the values. Field types which
+// implement [TestdataFiler] choose their own file instead.
+//
+// An optional ".gotconfig" within dir declares defaults for every field loaded
+// from (or saved to) that directory, which the struct tag options override.
+// It is a JSON object with "indent" (as with the "indent" option), "strict"
+// (as with Options.StrictLoad, but for Assert too) and "text" (as with the
+// "text" option), such as {"indent": 4, "text": true}.
 func Load(t tester, dir string, values ...any) {
 	t.Helper()
 
-	log := &logger{
-		t:      t,
-		prefix: "[GoT] Load: ",
-	}
+	Options{}.Load(t, dir, values...)
+}
 
-	if err := loadDirs(log, []string{dir}, values...); err != nil {
-		t.Fatalf("[GoT] Load: %s", err.Error())
-	}
+// LoadT is the same as Load, but allocates and returns a new T rather than
+// loading into an existing value (eg: `config := got.LoadT[Config](t, dir)`).
+func LoadT[T any](t tester, dir string) T {
+	t.Helper()
+
+	var value T
+	Load(t, dir, &value)
+	return value
 }
 
 // LoadDirs is the same as Load but accepts multiple input directories, which
 // can be used to set up test cases from a common/shared location while allowing
 // an individual test-case to include it's own specific configuration.
+//
+// Fields using the "explode" option are merged across all the directories,
+// with files from later directories overriding the same keys from earlier ones.
 func LoadDirs(t tester, dirs []string, values ...any) {
 	t.Helper()
 
-	log := &logger{
-		t:      t,
-		prefix: "[GoT] Load: ",
-	}
+	Options{}.LoadDirs(t, dirs, values...)
+}
 
-	if err := loadDirs(log, dirs, values...); err != nil {
-		t.Fatalf("[GoT] LoadDirs: %s", err.Error())
-	}
+// LoadOnly is the same as Load, but only the named fields of value are loaded
+// and the rest are left untouched, which is useful for skipping fields with
+// large (or missing) files when they are not needed.
+func LoadOnly(t tester, dir string, fields []string, value any) {
+	t.Helper()
+
+	Options{only: fields}.Load(t, dir, value)
 }
 
 // Assert ensures that all the fields within the struct values match what is on
@@ -79,308 +197,1549 @@ func LoadDirs(t tester, dirs []string, values ...any) {
 //
 // When the "test.update-golden" flag is provided, the contents of each value
 // struct will be persisted to disk instead. This allows any test to easily
-// update their "golden files" and also do the assertion transparently.
+// update their "golden files" and also do the assertion transparently. To
+// guard against accidental updates (eg: in CI), setting the GOT_NO_UPDATE
+// environment variable will make any attempted update fail the test instead.
+//
+// Slice fields can use the "unordered" option to compare them as sets, such as
+// `testdata:"tags.json,unordered"`, which saves them in a sorted order.
+//
+// Hand-maintained golden files can use the "frozen" option, which prevents
+// them from being updated while still comparing against them.
+//
+// The "indent" option overrides the indentation of the codec when saving a
+// single field, such as `testdata:"output.json,indent=4"`.
+//
+// Fields with volatile data (eg: timestamps, request IDs) can use the
+// "ignorekeys" option to remove keys from both sides before comparing as well
+// as before saving, such as `testdata:"output.json,ignorekeys=time|meta.id"`.
+//
+// Raw []byte fields (eg: json.RawMessage) holding encoded data can use the
+// "comparecodec" option to decode both sides with the named codec before
+// comparing, so formatting and key order are ignored while the contents are
+// still saved as-is, such as `testdata:"body.json,comparecodec=json"`.
+//
+// Fields holding json.RawMessage values (eg: a map[string]json.RawMessage) can
+// use the "jsoneq" option to compare them ignoring insignificant whitespace,
+// since they are re-indented when saved, such as `testdata:"input.json,jsoneq"`.
+//
+// Unexported fields of the values are ignored, since they are never loaded.
+// Fields holding structs with their own unexported fields (eg: a cache) can
+// use the "ignoreunexported" option to ignore those as well, such as
+// `testdata:"output.json,ignoreunexported"`.
+//
+// Fields containing time.Time values can use the "utc" option so they are
+// saved in UTC even when the actual value uses another zone, such as
+// `testdata:"event.json,utc"`. Times are always compared as instants.
 func Assert(t tester, dir string, values ...any) {
 	t.Helper()
 
-	log := &logger{
-		t:      t,
-		prefix: "[GoT] Assert: ",
-	}
-
-	if err := assert(log, dir, values...); err != nil {
-		t.Fatalf("[GoT] Assert: %s", err.Error())
-	}
+	Options{}.Assert(t, dir, values...)
 }
 
-func assert(log *logger, dir string, values ...any) error {
+func (o Options) assert(log *logger, dir string, values ...any) error {
 	if len(values) == 0 {
 		return errors.New("at least 1 value required")
 	}
 
+	if updateGolden && os.Getenv(lockEnv) != "" {
+		return fmt.Errorf("golden files cannot be updated while %s is set", lockEnv)
+	}
+
+	if err := o.checkSkipUpdate(values); err != nil {
+		return err
+	}
+
+	var errs []error
 	for _, actual := range values {
-		if updateGolden {
-			if err := saveDir(log, dir, actual); err != nil {
-				return err
+		if err := o.assertValue(log, dir, actual); err != nil {
+			errs = append(errs, err)
+
+			if !o.CollectAll {
+				break
 			}
+		}
+	}
 
-			continue
+	if o.WriteDiff && !updateGolden {
+		if err := writeDiffFile(o.diffFile(dir), errs); err != nil {
+			return err
 		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
 
-		expected := reflect.New(reflect.TypeOf(actual).Elem()).Interface()
+	failures := make([]string, len(errs))
+	for i, err := range errs {
+		failures[i] = err.Error()
+	}
 
-		if err := loadDirs(log, []string{dir}, expected); err != nil {
-			return err
+	return errors.New(strings.Join(failures, "\n"))
+}
+
+// diffFile returns the path the diff for dir is written to, which is named
+// after dir and placed either next to it or within DiffDir.
+func (o Options) diffFile(dir string) string {
+	file := filepath.Clean(dir) + ".diff"
+	if o.DiffDir != "" {
+		file = filepath.Join(o.DiffDir, filepath.Base(file))
+	}
+	return file
+}
+
+// writeDiffFile writes the diffs for any comparison failures in errs to file,
+// removing any stale file when there are none.
+func writeDiffFile(file string, errs []error) error {
+	var diffs []string
+	for _, err := range errs {
+		var derr *diffError
+		if errors.As(err, &derr) {
+			diffs = append(diffs, derr.diff)
 		}
+	}
 
-		if !cmp.Equal(expected, actual) {
-			return fmt.Errorf("test of %s failed: %s", getTypeName(expected), cmp.Diff(expected, actual))
+	if len(diffs) == 0 {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove diff file %s: %w", file, err)
 		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create dir for diff file %s: %w", file, err)
+	}
+
+	if err := os.WriteFile(file, []byte(strings.Join(diffs, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to write diff file %s: %w", file, err)
 	}
 
 	return nil
 }
 
-func loadDirs(log *logger, inputs []string, outputs ...any) error {
-	if len(outputs) == 0 {
-		return errors.New("at least 1 output required")
+// diffError indicates that the expected and actual values did not match.
+type diffError struct {
+	typ  string
+	diff string
+}
+
+func (e *diffError) Error() string {
+	return fmt.Sprintf("test of %s failed: %s", e.typ, e.diff)
+}
+
+func (o Options) assertValue(log *logger, dir string, actual any) error {
+	actual, err := o.prepareActual(dir, actual)
+	if err != nil {
+		return err
 	}
 
-	for _, output := range outputs {
-		if output == nil {
-			return errors.New("output cannot be nil")
+	if updateGolden {
+		if err := o.saveDir(log, dir, actual); err != nil {
+			return err
 		}
 
-		vlog := log.WithPrefix(getTypeName(output))
+		recordSummary(func(c *SummaryCounts) { c.Updated++ })
+
+		return o.assertFrozen(log, dir, actual)
+	}
 
-		if err := loadDir(vlog, inputs, output); err != nil {
+	if o.AbsentIsEmpty {
+		if err := checkAbsent(dir, actual); err != nil {
 			return err
 		}
 	}
 
-	return nil
-}
+	expected := reflect.New(reflect.TypeOf(actual).Elem()).Interface()
 
-func loadDir(log *logger, inputs []string, output any) error {
-	if k := reflect.TypeOf(output).Kind(); k != reflect.Ptr {
-		return fmt.Errorf("output must be a pointer, but got %s", k)
+	if err := o.loadDirs(log, []string{dir}, expected); err != nil {
+		return err
 	}
 
-	typ := reflect.TypeOf(output).Elem()
-	val := reflect.ValueOf(output).Elem()
+	expected, err = o.ignoreKeys(dir, expected)
+	if err != nil {
+		return err
+	}
+
+	expected, err = normalizeValues(expected)
+	if err != nil {
+		return err
+	}
 
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		value := val.Field(i)
+	opts := compareOptions(actual)
 
-		tags, err := structtag.Parse(string(field.Tag))
-		if err != nil {
-			return fmt.Errorf("%s.%s: failed to parse struct tags: %w", getTypeName(output), field.Name, err)
+	if o.WriteActual {
+		if err := o.writeActualFiles(log.WithPrefix(getTypeName(actual)), dir, expected, actual, opts); err != nil {
+			return err
 		}
+	}
 
-		tag, err := tags.Get(tagName)
+	if o.canReview() {
+		resolved, err := o.reviewFiles(log.WithPrefix(getTypeName(actual)), dir, expected, actual, opts)
 		if err != nil {
-			continue
-		} else if tag.Name == "" || tag.Name == "-" {
-			continue
+			return err
+		} else if resolved {
+			return nil
 		}
+	}
 
-		for _, input := range inputs {
-			if err := loadDirInput(log, input, tag, field, value); err != nil {
-				return fmt.Errorf("%s.%s: %w", getTypeName(output), field.Name, err)
-			}
-		}
+	if !cmp.Equal(expected, actual, opts...) {
+		return &diffError{typ: getTypeName(expected), diff: cmp.Diff(expected, actual, opts...)}
 	}
 
 	return nil
 }
 
-func loadDirInput(log *logger, input string, tag *structtag.Tag, field reflect.StructField, value reflect.Value) error {
-	file := filepath.Join(input, tag.Name)
+// checkChannels ensures input has no channel fields, which can only be loaded
+// since they cannot be saved or compared.
+func checkChannels(input any) error {
+	if input == nil || reflect.TypeOf(input).Kind() != reflect.Ptr || reflect.TypeOf(input).Elem().Kind() != reflect.Struct {
+		return nil // invalid inputs are reported elsewhere
+	}
 
-	if isMap(field.Type) && tag.HasOption("explode") {
-		matches, err := filepath.Glob(file)
-		if err != nil {
-			return fmt.Errorf("failed to list files %s: %w", file, err)
+	return walkFields(input, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		if field.Type.Kind() == reflect.Chan {
+			return fmt.Errorf("%s.%s: channel fields can only be loaded, not asserted", getTypeName(input), fieldName(field, tag))
+		}
+		return nil
+	})
+}
+
+// actualExt is appended to the name of a golden file to get the file where the
+// actual value is written when WriteActual is enabled.
+const actualExt = ".actual"
+
+// writeActualFiles writes the actual value for each field of actual that does
+// not match expected to a file next to the golden file, removing any stale
+// files for the fields that do match.
+func (o Options) writeActualFiles(log *logger, dir string, expected, actual any, opts []cmp.Option) error {
+	want := reflect.ValueOf(expected).Elem()
+
+	return walkDirFields(dir, actual, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		if frontMatterPart(tag) != "" || tag.HasOption(concatOption) {
+			return nil // only part of a file, so there is no value to write
+		} else if isRemote(tag.Name) {
+			return nil // not a local file, so there is nowhere to write it
 		}
 
-		m := reflect.MakeMap(field.Type)
+		log := log.WithPrefix("." + fieldName(field, tag))
+		expectedValue := want.FieldByIndex(field.Index)
 
-		for _, match := range matches {
-			rel, err := filepath.Rel(input, match)
+		if isExplodeSlice(field.Type, tag) {
+			files, err := explodeSliceFiles(tag.Name, value.Len())
 			if err != nil {
-				return fmt.Errorf("failed to resolve file %s: %w", match, err)
+				return err
 			}
 
-			key := reflect.ValueOf(rel)
-			val := reflect.New(m.Type().Elem()).Elem()
-			prefix := "." + field.Name + "[" + strconv.Quote(key.String()) + "]"
+			for i, file := range files {
+				var equal bool
+				if i < expectedValue.Len() {
+					equal = cmp.Equal(expectedValue.Index(i).Interface(), value.Index(i).Interface(), opts...)
+				}
 
-			if err := loadFile(log.WithPrefix(prefix), match, val); err != nil {
-				return fmt.Errorf("%s: %w", field.Name, err)
+				if err := o.writeActualFile(log, filepath.Join(dir, file), field, value.Index(i), equal); err != nil {
+					return err
+				}
 			}
 
-			m.SetMapIndex(key, val)
+			return nil
 		}
 
-		if m.Len() > 0 {
-			value.Set(m)
-		} else {
-			log.WithPrefix("." + field.Name).Log("no matches found")
-		}
+		if isMap(field.Type) && tag.HasOption("explode") {
+			for _, key := range value.MapKeys() {
+				file := explodeFile(dir, tag, key.String())
+				val := value.MapIndex(key)
 
-		return nil
-	}
+				var equal bool
+				if expectedVal := expectedValue.MapIndex(key); expectedVal.IsValid() {
+					equal = cmp.Equal(expectedVal.Interface(), val.Interface(), opts...)
+				}
 
-	if err := loadFile(log.WithPrefix("."+field.Name), file, value); err != nil {
-		return err
-	}
+				if err := o.writeActualFile(log, file, field, val, equal); err != nil {
+					return err
+				}
+			}
 
-	return nil
+			return nil
+		}
+
+		file := platformFile(osFS, dir, alternateName(osFS, dir, testdataFile(field, tag, value)))
+		equal := cmp.Equal(expectedValue.Interface(), value.Interface(), opts...)
+
+		return o.writeActualFile(log, file, field, value, equal)
+	})
 }
 
-func loadFile(log *logger, file string, value reflect.Value) error {
-	f, err := openTagFile(file)
-	if err != nil {
-		return err
-	} else if f == nil {
-		log.Log("skipped: file %q not found", file)
-		return nil
-	}
+func (o Options) writeActualFile(log *logger, file string, field reflect.StructField, val reflect.Value, equal bool) error {
+	actualFile := file + actualExt
 
-	data, err := io.ReadAll(f)
-	if err != nil {
-		return fmt.Errorf("file %q read error: %w", file, err)
+	// the actual file is never compressed, so it can be compared in an editor
+	codecFile := fieldPipeline(field).codecFile(file)
+
+	var data []byte
+	if !equal {
+		var err error
+		if data, _, err = o.encode(codecFile, field, val); err != nil {
+			return fmt.Errorf("failed to encode file %q: %w", actualFile, err)
+		}
+
+		if data, err = formatData(codecFile, data); err != nil {
+			return fmt.Errorf("failed to format file %q: %w", actualFile, err)
+		}
 	}
 
-	// raw types
-	if isBytes(value.Type()) {
-		value.SetBytes(data)
-		log.Log("loaded file %q as bytes (size %d)", file, len(data))
-		return nil
-	} else if isString(value.Type()) {
-		value.SetString(string(data))
-		log.Log("loaded file %q as string (size %d)", file, len(data))
+	if len(data) == 0 {
+		if err := os.Remove(actualFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete file %s: %w", actualFile, err)
+		}
 		return nil
 	}
 
-	ext := filepath.Ext(file)
-	codec, err := codec.Get(ext)
-	if err != nil {
-		return fmt.Errorf("failed to get codec for file extension %q", ext)
+	if err := os.MkdirAll(filepath.Dir(actualFile), 0755); err != nil {
+		return fmt.Errorf("failed to create dir %s: %w", filepath.Dir(actualFile), err)
 	}
 
-	p := reflect.New(value.Type())
-	p.Elem().Set(value) // preserve any prior values
-	if err := codec.Unmarshal(data, p.Interface()); err != nil {
-		return fmt.Errorf("file %q decode error: %w", file, err)
+	if err := os.WriteFile(actualFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", actualFile, err)
 	}
-	value.Set(p.Elem()) // overwrite with the updated value
-	log.Log("loaded file %q as %s (size %d)", file, codec.Name(), len(data))
+
+	log.Log("saved actual file %q (size %d)", actualFile, len(data))
 	return nil
 }
 
-func saveDir(log *logger, dir string, input any) error {
-	if input == nil {
-		return errors.New("input cannot be nil")
+func (o Options) loadDirs(log *logger, inputs []string, outputs ...any) error {
+	if len(outputs) == 0 {
+		return errors.New("at least 1 output required")
 	}
 
-	if k := reflect.TypeOf(input).Kind(); k != reflect.Ptr {
-		return fmt.Errorf("input must be a pointer, instead got %s", k)
+	for _, output := range outputs {
+		if output == nil {
+			return errors.New("output cannot be nil")
+		}
 	}
 
-	typ := reflect.TypeOf(input).Elem()
-	val := reflect.ValueOf(input).Elem()
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		value := val.Field(i)
-
-		tags, err := structtag.Parse(string(field.Tag))
+	manifests := make([]map[string]string, len(inputs))
+	for i, input := range inputs {
+		manifest, err := readManifest(o.files(), input)
 		if err != nil {
-			return fmt.Errorf("%s.%s: failed to parse struct tags: %w", getTypeName(input), field.Name, err)
+			return err
 		}
+		manifests[i] = manifest
+	}
 
-		tag, err := tags.Get(tagName)
-		if err != nil {
-			continue
-		} else if tag.Name == "" || tag.Name == "-" {
-			continue
-		}
+	if err := checkManifests(inputs, manifests, outputs); err != nil {
+		return err
+	}
+
+	for _, output := range outputs {
+		vlog := log.WithPrefix(getTypeName(output))
 
-		if err := saveDirField(log.WithPrefix(fmt.Sprintf("%s.%s", getTypeName(input), field.Name)), dir, tag, field, value); err != nil {
-			return fmt.Errorf("%s.%s error: %w", getTypeName(input), field.Name, err)
+		if err := o.loadDir(vlog, inputs, manifests, output); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func saveDirField(log *logger, dir string, tag *structtag.Tag, field reflect.StructField, value reflect.Value) error {
-	if isMap(field.Type) && tag.HasOption("explode") {
-		keys := value.MapKeys()
-		sort.Slice(keys, func(i, j int) bool {
-			return keys[i].String() < keys[j].String()
-		})
+func (o Options) loadDir(log *logger, inputs []string, manifests []map[string]string, output any) error {
+	if k := reflect.TypeOf(output).Kind(); k != reflect.Ptr {
+		return fmt.Errorf("output must be a pointer, but got %s", k)
+	}
 
-		for _, k := range keys {
-			v := value.MapIndex(k)
+	typ := reflect.TypeOf(output).Elem()
+	val := reflect.ValueOf(output).Elem()
 
-			file := filepath.Join(dir, k.String())
-			if err := saveFile(log, file, v); err != nil {
-				return err
-			}
-		}
+	if o.Reset {
+		val.Set(reflect.Zero(typ))
+	}
 
-		return nil
+	if isDecoderOnly(output) {
+		return decodeTestdata(log, inputs, output)
 	}
 
-	file := filepath.Join(dir, tag.Name)
-	if err := saveFile(log, file, value); err != nil {
-		return err
+	o.inputs = inputs
+
+	configs := make([]Options, len(inputs))
+	for i, input := range inputs {
+		var err error
+		if configs[i], err = o.withDirConfig(input); err != nil {
+			return err
+		}
 	}
 
-	return nil
-}
+	if err := checkOnly(o.only, output); err != nil {
+		return err
+	}
 
-func saveFile(log *logger, file string, val reflect.Value) error {
-	data, err := encode(file, val)
+	parts, err := concatParts(output)
 	if err != nil {
-		return fmt.Errorf("failed to encode file %q: %w", file, err)
+		return err
 	}
 
-	if len(data) == 0 {
-		if err := os.Remove(file); err != nil {
-			if !os.IsNotExist(err) {
-				return fmt.Errorf("failed to delete file %s: %w", file, err)
+	err = walkFields(output, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		if o.only != nil && !contains(o.only, field.Name) {
+			return nil
+		}
+
+		if typ := unsupportedType(field.Type); typ != nil {
+			return fmt.Errorf("%s.%s: unsupported type %s", getTypeName(output), fieldName(field, tag), typ)
+		}
+
+		if isRemote(tag.Name) {
+			if err := o.loadRemote(log.WithPrefix("."+fieldName(field, tag)), tag, field, value); err != nil {
+				return fmt.Errorf("%s.%s: %w", getTypeName(output), fieldName(field, tag), err)
 			}
+			return nil
 		}
 
-		log.Log("removed file %q: empty", file)
-	} else {
-		dir := filepath.Dir(file)
+		flog := log
+		if o.QuietLayers && len(inputs) > 1 {
+			flog = log.buffered()
+		}
 
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create dir %s: %w", dir, err)
+		for i, input := range inputs {
+			tag := manifestTag(manifests[i], field, tag)
+
+			if flog.buffer != nil {
+				flog.buffer.input = i
+			}
+
+			if err := configs[i].loadDirInput(flog, input, tag, field, value, parts[field.Name]); err != nil {
+				if flog.buffer != nil {
+					flog.flush()
+				}
+				return fmt.Errorf("%s.%s: %w", getTypeName(output), fieldName(field, tag), err)
+			}
 		}
 
-		if err := os.WriteFile(file, data, 0644); err != nil {
-			return fmt.Errorf("failed to write file %s: %w", file, err)
+		if flog.buffer != nil {
+			flog.flush()
 		}
 
-		log.Log("saved file %q (size %d)", file, len(data))
+		if tag.HasOption(utcOption) {
+			if err := setUTC(field, value); err != nil {
+				return fmt.Errorf("%s.%s: %w", getTypeName(output), fieldName(field, tag), err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// the named fields are the only ones loaded, which excludes DecodeTestdata
+	if o.only != nil {
+		return nil
 	}
 
-	return nil
+	return decodeTestdata(log, inputs, output)
 }
 
-func encode(file string, val reflect.Value) ([]byte, error) {
-	switch {
-	case val.IsZero():
-		return nil, nil
-	case isBytes(val.Type()):
-		return val.Bytes(), nil
-	case isString(val.Type()):
-		return []byte(val.String()), nil
+// checkOnly ensures every field named in only is a field of output with a
+// "testdata" struct tag.
+func checkOnly(only []string, output any) error {
+	if only == nil {
+		return nil
 	}
 
-	ext := filepath.Ext(file)
-	codec, err := codec.Get(ext)
+	var fields []string
+	err := walkFields(output, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		fields = append(fields, field.Name)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get codec for file extension %q", ext)
+		return err
 	}
-	return codec.Marshal(val.Interface())
-}
 
-func openTagFile(file string) (*os.File, error) {
-	f, err := os.Open(file)
-	if err != nil {
-		// suppress "not found" errors
-		if os.IsNotExist(err) {
-			return nil, nil
+	for _, name := range only {
+		if !contains(fields, name) {
+			return fmt.Errorf("unknown field %s.%s", getTypeName(output), name)
 		}
-		return nil, err
 	}
-	return f, nil
+
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// manifestFile is an optional file within an input directory which maps field
+// names to the file (or pattern) they should be loaded from, overriding the
+// name in the struct tag (eg: {"Input": "alternate.json"}).
+const manifestFile = "manifest.json"
+
+// checkManifests ensures every field referenced by a manifest is a field of at
+// least one of the outputs with a "testdata" struct tag, so typos are not
+// silently ignored.
+func checkManifests(inputs []string, manifests []map[string]string, outputs []any) error {
+	fields := make(map[string]bool)
+
+	for _, output := range outputs {
+		if typ := reflect.TypeOf(output); typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+			continue // invalid outputs are reported by loadDir
+		}
+
+		err := walkFields(output, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+			fields[field.Name] = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, manifest := range manifests {
+		for name := range manifest {
+			if fields[name] {
+				continue
+			}
+
+			// qualify the field when there is only 1 output it could belong to
+			if len(outputs) == 1 {
+				name = getTypeName(outputs[0]) + "." + name
+			}
+
+			return fmt.Errorf("manifest %s references unknown field %s", filepath.Join(inputs[i], manifestFile), name)
+		}
+	}
+
+	return nil
+}
+
+func readManifest(fsys fileSystem, dir string) (map[string]string, error) {
+	file := filepath.Join(dir, manifestFile)
+
+	data, err := readFS(fsys, file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", file, err)
+	}
+
+	var manifest map[string]string
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest %s: %w", file, err)
+	}
+
+	return manifest, nil
+}
+
+// manifestTag returns tag with the name overridden by manifest for field, if
+// there is one.
+func manifestTag(manifest map[string]string, field reflect.StructField, tag *structtag.Tag) *structtag.Tag {
+	name, ok := manifest[field.Name]
+	if !ok {
+		return tag
+	}
+
+	override := *tag
+	override.Name = name
+	return &override
+}
+
+// walkDirFields is the same as walkFields, but with the names overridden by the
+// manifest in dir, for saving to (or checking the files in) dir.
+func walkDirFields(dir string, input any, fn func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error) error {
+	manifest, err := readManifest(osFS, dir)
+	if err != nil {
+		return err
+	}
+
+	return walkFields(input, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		return fn(field, value, manifestTag(manifest, field, tag))
+	})
+}
+
+func (o Options) loadDirInput(log *logger, input string, tag *structtag.Tag, field reflect.StructField, value reflect.Value, concat *concatPart) error {
+	// there is no value to load from yet, so the zero value chooses the file
+	file := platformFile(o.files(), input, alternateName(o.files(), input, testdataFile(field, tag, reflect.Value{})))
+
+	if field.Type.Kind() == reflect.Map && !isMap(field.Type) && tag.HasOption("explode") {
+		return fmt.Errorf("explode requires a map with string keys, but got %s", field.Type)
+	}
+
+	schema, err := getSchema(o.files(), input, tag)
+	if err != nil {
+		return err
+	}
+
+	limit, err := getLimit(field.Type, tag)
+	if err != nil {
+		return err
+	}
+
+	resolveDir, err := getResolveDir(input, field.Type, tag)
+	if err != nil {
+		return err
+	}
+
+	opts := fileOptions{
+		schema:     schema,
+		part:       frontMatterPart(tag),
+		concat:     concat,
+		limit:      limit,
+		field:      field,
+		resolveDir: resolveDir,
+		pipeline:   o.newPipeline(tag),
+		nonempty:   tag.HasOption("nonempty"),
+	}
+
+	if tag.HasOption(includeOption) {
+		c, err := o.getCodec(opts.pipeline.codecFile(file), field)
+		if err != nil {
+			return err
+		}
+
+		if err := checkInclude(c); err != nil {
+			return err
+		}
+
+		opts.include = &includer{fsys: o.files(), dirs: includeDirs(input, o.inputs)}
+	}
+
+	if isExplodeSlice(field.Type, tag) {
+		return o.loadExplodeSlice(log.WithPrefix("."+fieldName(field, tag)), input, file, opts, tag, value)
+	}
+
+	if isMap(field.Type) && tag.HasOption("explode") {
+		matches, err := globFiles(o.files(), log.WithPrefix("."+fieldName(field, tag)), input, file, tag)
+		if err != nil {
+			return fmt.Errorf("failed to list files %s: %w", file, err)
+		}
+
+		if len(matches) == 0 {
+			if tag.HasOption("empty") && value.IsNil() {
+				value.Set(reflect.MakeMap(field.Type))
+				log.WithPrefix("." + fieldName(field, tag)).Log("no matches found, using an empty map")
+				return nil
+			}
+
+			log.WithPrefix("." + fieldName(field, tag)).Missing("no matches found")
+			return nil
+		}
+
+		// when loading from multiple dirs, merge into the map populated by
+		// any earlier dirs so later matches override earlier keys
+		m := value
+		if m.IsNil() {
+			m = reflect.MakeMapWithSize(field.Type, len(matches))
+			value.Set(m)
+		}
+
+		keys, err := getExplodeKeys(input, matches, tag)
+		if err != nil {
+			return err
+		}
+
+		for i, match := range matches {
+			key := reflect.ValueOf(keys[i])
+			val := reflect.New(m.Type().Elem()).Elem()
+			prefix := "." + fieldName(field, tag) + "[" + strconv.Quote(key.String()) + "]"
+
+			if err := o.loadFile(log.WithPrefix(prefix), match, opts, val); err != nil {
+				return fmt.Errorf("%s: %w", field.Name, err)
+			}
+
+			m.SetMapIndex(key, val)
+		}
+
+		return nil
+	}
+
+	if err := o.loadFile(log.WithPrefix("."+fieldName(field, tag)), file, opts, value); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// globFiles lists the files matching pattern for an explode field. A "**" path
+// segment matches any number of directories (eg: "requests/**/*.json"), while
+// a pattern ending with "**" matches every file under that directory. Any
+// directories matching the pattern are skipped (since only files can be
+// loaded), as are files matching the "exclude" option (a list of globs
+// separated by "|" which are checked against both the relative path and the
+// base name).
+func globFiles(fsys fileSystem, log *logger, input, pattern string, tag *structtag.Tag) ([]string, error) {
+	// each directory is listed once, which also tells whether each match is a
+	// directory without calling Stat
+	cache := newDirCache(fsys)
+
+	find := glob
+	if strings.Contains(pattern, "**") {
+		find = globTree
+	}
+
+	found, err := find(cache, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := found[:0]
+	for _, match := range found {
+		dir, err := cache.isDir(match)
+		if err != nil {
+			return nil, err
+		}
+
+		if dir {
+			log.Log("skipped: %q matches a directory, but explode only loads files", match)
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	exclude, ok := getTagOption(tag, "exclude")
+	if !ok {
+		return matches, nil
+	}
+
+	filtered := matches[:0]
+	for _, match := range matches {
+		rel, err := filepath.Rel(input, match)
+		if err != nil {
+			return nil, err
+		}
+
+		excluded, err := matchAny(strings.Split(exclude, "|"), filepath.ToSlash(rel))
+		if err != nil {
+			return nil, err
+		}
+
+		if !excluded {
+			filtered = append(filtered, match)
+		}
+	}
+
+	return filtered, nil
+}
+
+// matchAny reports whether the slash-separated rel path (or just it's base
+// name) matches any of the patterns.
+func matchAny(patterns []string, rel string) (bool, error) {
+	for _, pattern := range patterns {
+		for _, name := range []string{rel, path.Base(rel)} {
+			if ok, err := path.Match(pattern, name); err != nil {
+				return false, err
+			} else if ok {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// getExplodeKeys returns the map key for each of the matched files, which is
+// the path relative to input with the "strip" directory removed.
+func getExplodeKeys(input string, matches []string, tag *structtag.Tag) ([]string, error) {
+	keys := make([]string, len(matches))
+
+	// matches are usually prefixed by input already, which is much cheaper to
+	// trim than resolving with filepath.Rel for each of them
+	prefix := filepath.Clean(input) + string(filepath.Separator)
+
+	for i, match := range matches {
+		rel := strings.TrimPrefix(match, prefix)
+		if rel == match {
+			var err error
+			if rel, err = filepath.Rel(input, match); err != nil {
+				return nil, fmt.Errorf("failed to resolve file %s: %w", match, err)
+			}
+		}
+
+		key, ok := explodeKey(rel, tag)
+		if !ok {
+			return nil, fmt.Errorf("file %s is not within the strip directory %q", match, stripDir(tag))
+		}
+
+		keys[i] = key
+	}
+
+	return keys, nil
+}
+
+// stripDir returns the "strip" option for an exploded map, which is a
+// directory (relative to the dir being loaded or saved) that is removed from
+// the start of each key, ending with a "/" unless it is empty.
+func stripDir(tag *structtag.Tag) string {
+	strip, _ := getTagOption(tag, "strip")
+	if strip = path.Clean(strip); strip == "." {
+		return ""
+	}
+
+	return strip + "/"
+}
+
+// explodeKey returns the key for an exploded map given rel (the path of a file
+// relative to the dir it was loaded from), reporting false when the file is
+// not within the "strip" directory.
+func explodeKey(rel string, tag *structtag.Tag) (string, bool) {
+	strip := filepath.FromSlash(stripDir(tag))
+	if !strings.HasPrefix(rel, strip) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(rel, strip), true
+}
+
+// explodeFile returns the path to the file for key of an exploded map within
+// dir, which is the inverse of explodeKey.
+func explodeFile(dir string, tag *structtag.Tag, key string) string {
+	return filepath.Join(dir, filepath.FromSlash(stripDir(tag)+key))
+}
+
+// fileOptions are the settings from a struct tag which affect how each file
+// for that field is loaded.
+type fileOptions struct {
+	schema *jsonschema.Schema
+	part   string
+	concat *concatPart
+	limit  int
+	field  reflect.StructField
+
+	// resolveDir is where relative paths are resolved from, when enabled
+	resolveDir string
+
+	pipeline pipeline
+
+	// include reads the files included by the "include" option, when enabled
+	include *includer
+
+	// codecFile overrides the name of the file used to choose the formatter
+	// and codec, as used by remote files
+	codecFile string
+
+	// nonempty fails when the file exists but has no contents
+	nonempty bool
+}
+
+func (o Options) loadFile(log *logger, file string, opts fileOptions, value reflect.Value) error {
+	var timing *fileTiming
+	if o.Timing {
+		timing = new(fileTiming)
+	}
+
+	if o.CacheFiles && o.fsys == nil && opts.canShareCached(file, value) {
+		return o.loadCachedString(log, file, opts, value, timing)
+	}
+
+	data, found, err := o.readTagFile(file, timing)
+	if err != nil {
+		return err
+	} else if !found {
+		log.Missing("skipped: file %q not found", file)
+		return nil
+	}
+
+	if o.MaxFileSize > 0 && int64(len(data)) > o.MaxFileSize {
+		return fmt.Errorf("file %q exceeds max file size of %d bytes", file, o.MaxFileSize)
+	}
+
+	if opts.nonempty && len(data) == 0 {
+		return fmt.Errorf("file %q is empty, but the nonempty option requires contents", file)
+	}
+
+	start := time.Now()
+	if err := o.decodeFile(log, file, data, opts, value); err != nil {
+		return err
+	}
+
+	timing.setDecode(time.Since(start))
+	timing.log(log, file)
+	return nil
+}
+
+// decodeFile is the remainder of loadFile once the contents of file have been
+// read, which is shared with remote files.
+func (o Options) decodeFile(log *logger, file string, data []byte, opts fileOptions, value reflect.Value) error {
+	data, err := opts.pipeline.load(data, o.MaxFileSize)
+	if err != nil {
+		return fmt.Errorf("file %q %w", file, err)
+	}
+
+	codecFile := opts.pipeline.codecFile(file)
+	if opts.codecFile != "" {
+		codecFile = opts.pipeline.codecFile(opts.codecFile)
+	}
+
+	data, err = formatData(codecFile, data)
+	if err != nil {
+		return fmt.Errorf("file %q format error: %w", file, err)
+	}
+
+	switch header, body := splitFrontMatter(data); opts.part {
+	case frontMatterHeader:
+		data = header
+	case frontMatterBody:
+		data = body
+	}
+
+	if opts.concat != nil {
+		data = splitConcat(data, *opts.concat)
+	}
+
+	if opts.include != nil && len(data) > 0 {
+		if data, err = opts.include.resolveIncludes(file, data); err != nil {
+			return fmt.Errorf("file %q include error: %w", file, err)
+		}
+	}
+
+	// custom JSON types take precedence over raw types
+	if isJSONUnmarshaler(value.Type()) {
+		p := reflect.New(value.Type())
+		if err := p.Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+			return fmt.Errorf("file %q decode error: %w", file, err)
+		}
+		value.Set(p.Elem())
+		log.Log("loaded file %q via UnmarshalJSON (size %d)", file, len(data))
+		return nil
+	}
+
+	// raw types
+	if isBytes(value.Type()) {
+		value.SetBytes(data)
+		log.Log("loaded file %q as bytes (size %d)", file, len(data))
+		return nil
+	} else if isString(value.Type()) {
+		value.SetString(string(data))
+		log.Log("loaded file %q as string (size %d)", file, len(data))
+
+		if opts.resolveDir != "" {
+			resolvePaths(opts.resolveDir, value)
+		}
+
+		return nil
+	}
+
+	var c codec.Codec
+	if opts.part == frontMatterHeader {
+		c, err = codec.Get(".yaml")
+	} else {
+		c, err = o.getCodec(codecFile, opts.field)
+	}
+
+	// files without a registered extension are parsed as plain text for scalar
+	// types, or otherwise fall back to sniffing the contents
+	var uerr *unknownCodecError
+	if errors.As(err, &uerr) && isScalar(value.Type()) {
+		if err := parseScalar(data, value); err != nil {
+			return fmt.Errorf("file %q decode error: %w", file, err)
+		}
+		log.Log("loaded file %q as text (size %d)", file, len(data))
+		return nil
+	} else if errors.As(err, &uerr) {
+		if m, ok := codec.GetByMagic(data); ok {
+			if c, err = withCodecOptions(m, opts.field); err == nil {
+				log.Log("detected file %q as %s by magic bytes", file, m.Name())
+			}
+		}
+	}
+
+	if err != nil {
+		if o.AllowUnknownCodecs && errors.As(err, &uerr) {
+			log.Log("skipped: file %q has no registered codec", file)
+			return nil
+		}
+		return err
+	}
+
+	if o.strict {
+		c = withStrict(c)
+	}
+
+	if opts.schema != nil {
+		if err := validateSchema(c, opts.schema, data); err != nil {
+			return fmt.Errorf("file %q schema error: %w", file, err)
+		}
+	}
+
+	if value.Kind() == reflect.Chan {
+		// channels are populated from a decoded slice, buffered so that every
+		// element can be sent up front and then closed for the test to range over
+		p := reflect.New(reflect.SliceOf(value.Type().Elem()))
+		if err := c.Unmarshal(data, p.Interface()); err != nil {
+			return fmt.Errorf("file %q decode error: %w", file, err)
+		}
+
+		items := p.Elem()
+		ch := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, value.Type().Elem()), items.Len())
+		for i := 0; i < items.Len(); i++ {
+			ch.Send(items.Index(i))
+		}
+		ch.Close()
+
+		value.Set(ch)
+		log.Log("loaded file %q as %s into channel (size %d)", file, codec.Describe(c), len(data))
+		return nil
+	}
+
+	p := reflect.New(value.Type())
+	p.Elem().Set(value) // preserve any prior values
+	if err := c.Unmarshal(data, p.Interface()); err != nil {
+		return fmt.Errorf("file %q decode error: %w", file, err)
+	}
+	value.Set(p.Elem()) // overwrite with the updated value
+	log.Log("loaded file %q as %s (size %d)", file, codec.Describe(c), len(data))
+
+	if opts.resolveDir != "" {
+		resolvePaths(opts.resolveDir, value)
+	}
+
+	if opts.limit > 0 && value.Kind() == reflect.Slice && value.Len() > opts.limit {
+		log.Log("limited to %d of %d elements", opts.limit, value.Len())
+		value.Set(value.Slice(0, opts.limit))
+	}
+
+	return nil
+}
+
+// getLimit parses the "limit" option, which keeps only the first N elements of
+// a slice when loading (eg: `testdata:"items.json,limit=5"`). Fields using the
+// "explode" option apply the limit to each of the files instead.
+func getLimit(typ reflect.Type, tag *structtag.Tag) (int, error) {
+	option, ok := getTagOption(tag, "limit")
+	if !ok {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(option)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid limit %q", option)
+	}
+
+	if isMap(typ) && tag.HasOption("explode") {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Slice || isBytes(typ) {
+		return 0, fmt.Errorf("limit requires a slice, but got %s", typ)
+	}
+
+	return limit, nil
+}
+
+// prepareActual validates actual and applies the struct tag options which
+// change a value before it is compared or saved (eg: "ignore" and "format").
+func (o Options) prepareActual(dir string, actual any) (any, error) {
+	if err := checkChannels(actual); err != nil {
+		return nil, err
+	}
+
+	if err := checkCompareCodecs(actual); err != nil {
+		return nil, err
+	}
+
+	actual, err := o.ignoreKeys(dir, actual)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err = formatValues(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err = normalizeValues(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err = sortUnordered(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeUTC(actual)
+}
+
+func (o Options) saveDir(log *logger, dir string, input any) error {
+	if input == nil {
+		return errors.New("input cannot be nil")
+	}
+
+	if k := reflect.TypeOf(input).Kind(); k != reflect.Ptr {
+		return fmt.Errorf("input must be a pointer, instead got %s", k)
+	}
+
+	o, err := o.withDirConfig(dir)
+	if err != nil {
+		return err
+	}
+
+	parts, err := concatParts(input)
+	if err != nil {
+		return err
+	}
+
+	// front matter and concatenated files are made up of multiple fields, so
+	// they are only saved once all the fields have been encoded
+	var frontMatterFiles []string
+	frontMatter := make(map[string]*frontMatterFile)
+
+	var concatFiles []string
+	concat := make(map[string][][]byte)
+
+	err = walkDirFields(dir, input, func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		name := fmt.Sprintf("%s.%s", getTypeName(input), fieldName(field, tag))
+
+		if isRemote(tag.Name) {
+			log.WithPrefix(name).Log("skipped: remote file %q cannot be updated", tag.Name)
+			return nil
+		}
+
+		if tag.HasOption(frozenOption) && !o.saveFrozen {
+			log.WithPrefix(name).Log("skipped: field is frozen")
+			return nil
+		}
+
+		if tag.HasOption("expand") {
+			log.WithPrefix(name).Log("skipped: field uses expand, which cannot be reversed")
+			return nil
+		}
+
+		if o.skipUpdate(field) {
+			log.WithPrefix(name).Log("skipped: field is excluded by SkipUpdate")
+			return nil
+		}
+
+		if part := frontMatterPart(tag); part != "" {
+			file := platformFile(osFS, dir, tag.Name)
+
+			data, err := o.encodeFrontMatterPart(file, part, field, value)
+			if err != nil {
+				return fmt.Errorf("%s error: %w", name, err)
+			}
+
+			f, ok := frontMatter[file]
+			if !ok {
+				f = new(frontMatterFile)
+				frontMatter[file] = f
+				frontMatterFiles = append(frontMatterFiles, file)
+			}
+
+			if part == frontMatterHeader {
+				f.header = data
+			} else {
+				f.body = data
+			}
+
+			return nil
+		}
+
+		if part := parts[field.Name]; part != nil {
+			file := platformFile(osFS, dir, tag.Name)
+
+			data, _, err := o.encode(file, field, value)
+			if err != nil {
+				return fmt.Errorf("%s error: %w", name, err)
+			}
+
+			sections, ok := concat[file]
+			if !ok {
+				sections = make([][]byte, part.count)
+				concat[file] = sections
+				concatFiles = append(concatFiles, file)
+			}
+			sections[part.index] = data
+
+			return nil
+		}
+
+		if err := o.saveDirField(log.WithPrefix(name), dir, tag, field, value); err != nil {
+			return fmt.Errorf("%s error: %w", name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, file := range frontMatterFiles {
+		f := frontMatter[file]
+		data := joinFrontMatter(f.header, f.body)
+
+		if err := o.saveFile(log.WithPrefix(getTypeName(input)), file, reflect.StructField{}, reflect.ValueOf(data)); err != nil {
+			return fmt.Errorf("%s error: %w", getTypeName(input), err)
+		}
+	}
+
+	for _, file := range concatFiles {
+		data, err := joinConcat(concat[file])
+		if err != nil {
+			return fmt.Errorf("%s error: failed to concat file %q: %w", getTypeName(input), file, err)
+		}
+
+		if err := o.saveFile(log.WithPrefix(getTypeName(input)), file, reflect.StructField{}, reflect.ValueOf(data)); err != nil {
+			return fmt.Errorf("%s error: %w", getTypeName(input), err)
+		}
+	}
+
+	return nil
+}
+
+func (o Options) saveDirField(log *logger, dir string, tag *structtag.Tag, field reflect.StructField, value reflect.Value) error {
+	if typ := unsupportedType(field.Type); typ != nil {
+		return fmt.Errorf("unsupported type %s", typ)
+	}
+
+	if field.Type.Kind() == reflect.Map && !isMap(field.Type) && tag.HasOption("explode") {
+		return fmt.Errorf("explode requires a map with string keys, but got %s", field.Type)
+	}
+
+	resolveDir, err := getResolveDir(dir, field.Type, tag)
+	if err != nil {
+		return err
+	} else if resolveDir != "" {
+		value = relativePaths(resolveDir, value)
+	}
+
+	if isExplodeSlice(field.Type, tag) {
+		return o.saveExplodeSlice(log, dir, tag, field, value)
+	}
+
+	if isMap(field.Type) && tag.HasOption("explode") {
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+
+		for _, k := range keys {
+			v := value.MapIndex(k)
+
+			file := explodeFile(dir, tag, k.String())
+			if err := o.saveFile(log, file, field, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	file := platformFile(osFS, dir, alternateName(osFS, dir, testdataFile(field, tag, value)))
+	if err := o.saveFile(log, file, field, value); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o Options) saveFile(log *logger, file string, field reflect.StructField, val reflect.Value) error {
+	p := o.fieldPipeline(field)
+	codecFile := p.codecFile(file)
+
+	data, c, err := o.encode(codecFile, field, val)
+	if err != nil {
+		var uerr *unknownCodecError
+		if o.AllowUnknownCodecs && errors.As(err, &uerr) {
+			log.Log("skipped: file %q has no registered codec", file)
+			return nil
+		}
+		return fmt.Errorf("failed to encode file %q: %w", file, err)
+	}
+
+	data, err = formatData(codecFile, data)
+	if err != nil {
+		return fmt.Errorf("failed to format file %q: %w", file, err)
+	}
+
+	data, err = normalizeData(codecFile, data)
+	if err != nil {
+		return fmt.Errorf("failed to normalize file %q: %w", file, err)
+	}
+
+	data, err = p.save(data)
+	if err != nil {
+		return fmt.Errorf("failed to save file %q: %w", file, err)
+	}
+
+	// a nil pointer to a scalar is saved as an empty file (see isNilScalar)
+	keep := len(data) == 0 && o.isNilScalar(codecFile, field, val)
+
+	if o.plan != nil {
+		if len(data) > 0 || keep {
+			o.plan.add(file, data)
+		}
+		return nil
+	}
+
+	if len(data) == 0 && !keep {
+		if err := os.Remove(file); err != nil {
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("failed to delete file %s: %w", file, err)
+			}
+		}
+
+		log.Log("removed file %q: empty", file)
+	} else {
+		dir := filepath.Dir(file)
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create dir %s: %w", dir, err)
+		}
+
+		if err := os.WriteFile(file, data, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", file, err)
+		}
+
+		if d, ok := c.(codec.Describer); ok {
+			log.Log("saved file %q as %s (size %d)", file, d.Describe(), len(data))
+		} else {
+			log.Log("saved file %q (size %d)", file, len(data))
+		}
+	}
+
+	return nil
+}
+
+// encode returns the contents to save for val, along with the codec that was
+// used (which is nil for raw types).
+func (o Options) encode(file string, field reflect.StructField, val reflect.Value) ([]byte, codec.Codec, error) {
+	switch {
+	case val.IsZero():
+		return nil, nil, nil
+	case isJSONMarshaler(val.Type()):
+		data, err := val.Interface().(json.Marshaler).MarshalJSON()
+		return data, nil, err
+	case isBytes(val.Type()):
+		return val.Bytes(), nil, nil
+	case isString(val.Type()):
+		return []byte(val.String()), nil, nil
+	}
+
+	c, err := o.getCodec(file, field)
+
+	var uerr *unknownCodecError
+	if errors.As(err, &uerr) && isScalar(val.Type()) {
+		return formatScalar(val), nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := c.Marshal(val.Interface())
+	return data, c, err
+}
+
+// unknownCodecError indicates that no codec has been registered for the
+// extension of a file.
+type unknownCodecError struct {
+	ext string
+}
+
+func (e *unknownCodecError) Error() string {
+	return fmt.Sprintf("failed to get codec for file extension %q", e.ext)
+}
+
+// getCodec resolves the codec for file, which is the codec named by the "codec"
+// option of field when it is set, or otherwise consults o.CodecResolver before
+// falling back to the extension. Any defaults from the configFile and codec
+// options from the struct tag of field are then applied to a copy of that
+// codec.
+func (o Options) getCodec(file string, field reflect.StructField) (codec.Codec, error) {
+	if tag, ok := fieldTag(field); ok {
+		if name, ok := getTagOption(tag, "codec"); ok {
+			c, err := codec.GetByName(name)
+			if err != nil {
+				return nil, err
+			}
+			return withCodecOptions(o.withConfigIndent(c), field)
+		}
+	}
+
+	if o.CodecResolver != nil {
+		if c, ok := o.CodecResolver(file, field); ok {
+			return withCodecOptions(o.withConfigIndent(c), field)
+		}
+	}
+
+	c, err := getCodec(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return withCodecOptions(o.withConfigIndent(c), field)
+}
+
+// withCodecOptions applies the codec options from the struct tag of field to a
+// copy of c, leaving the registered codec untouched. The "indent" option sets
+// the indentation for codecs which implement codec.Indenter (eg:
+// `testdata:"output.json,indent=4"`).
+func withCodecOptions(c codec.Codec, field reflect.StructField) (codec.Codec, error) {
+	tag, ok := fieldTag(field)
+	if !ok {
+		return c, nil
+	}
+
+	option, ok := getTagOption(tag, "indent")
+	if !ok {
+		return c, nil
+	}
+
+	indent, err := strconv.Atoi(option)
+	if err != nil || indent < 0 {
+		return nil, fmt.Errorf("invalid indent %q", option)
+	}
+
+	if _, ok := c.(codec.Indenter); !ok {
+		return nil, fmt.Errorf("codec %s does not support indentation", c.Name())
+	}
+
+	c = copyCodec(c)
+	c.(codec.Indenter).SetIndent(indent)
+	return c, nil
+}
+
+// withStrict returns a copy of c which rejects unknown fields when decoding,
+// or c itself when it does not implement codec.Stricter.
+func withStrict(c codec.Codec) codec.Codec {
+	if _, ok := c.(codec.Stricter); !ok {
+		return c
+	}
+
+	c = copyCodec(c)
+	c.(codec.Stricter).SetStrict(true)
+	return c
+}
+
+// copyCodec returns a shallow copy of c when it is a pointer, so it can be
+// configured without changing the registered codec.
+func copyCodec(c codec.Codec) codec.Codec {
+	if v := reflect.ValueOf(c); v.Kind() == reflect.Ptr {
+		p := reflect.New(v.Elem().Type())
+		p.Elem().Set(v.Elem())
+		c = p.Interface().(codec.Codec)
+	}
+	return c
+}
+
+// getCodec resolves the codec for file using its extension. When the extension
+// belongs to a registered layer (eg: "data.json.b64"), the codec is resolved
+// from the remaining extensions and chained with that layer.
+func getCodec(file string) (codec.Codec, error) {
+	ext := filepath.Ext(file)
+	if layer, err := codec.GetLayer(ext); err == nil {
+		inner, err := getCodec(strings.TrimSuffix(file, ext))
+		if err != nil {
+			return nil, err
+		}
+		return codec.Chain(layer, inner), nil
+	}
+
+	c, err := codec.Get(ext)
+	if err != nil {
+		return nil, &unknownCodecError{ext: ext}
+	}
+	return c, nil
+}
+
+// readFile reads the contents of f, up to 1 byte beyond max (when set) so that
+// oversized files can be detected. The buffer is sized using the file info up
+// front, which avoids repeated allocations when loading many files.
+func readFile(f fs.File, max int64) ([]byte, error) {
+	var r io.Reader = f
+	if max > 0 {
+		r = io.LimitReader(f, max+1)
+	}
+
+	size := int64(512)
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		size = info.Size() + 1 // room to detect EOF without growing
+	}
+	if max > 0 && size > max+1 {
+		size = max + 1
+	}
+
+	data := make([]byte, 0, size)
+	for {
+		n, err := r.Read(data[len(data):cap(data)])
+		data = data[:len(data)+n]
+		if errors.Is(err, io.EOF) {
+			return data, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(data) == cap(data) {
+			data = append(data, 0)[:len(data)]
+		}
+	}
+}
+
+func openTagFile(fsys fileSystem, file string) (fs.File, error) {
+	f, err := fsys.Open(file)
+	if err != nil {
+		// suppress "not found" errors
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// fieldName returns the name of field for use in logs and errors, including
+// the "desc" option when set (eg: `testdata:"input.json,desc=login request"`).
+func fieldName(field reflect.StructField, tag *structtag.Tag) string {
+	if desc, ok := getTagOption(tag, "desc"); ok {
+		return fmt.Sprintf("%s (%s)", field.Name, desc)
+	}
+
+	return field.Name
+}
+
+// getTagOption returns the value for an option formatted as "name=value".
+func getTagOption(tag *structtag.Tag, name string) (string, bool) {
+	prefix := name + "="
+
+	for _, option := range tag.Options {
+		if strings.HasPrefix(option, prefix) {
+			return strings.TrimPrefix(option, prefix), true
+		}
+	}
+
+	return "", false
+}
+
+// unsupportedType returns the type within typ which cannot be meaningfully
+// loaded or saved (eg: func), or nil when the entire type is supported.
+func unsupportedType(typ reflect.Type) reflect.Type {
+	return findUnsupportedType(typ, make(map[reflect.Type]bool))
+}
+
+// findUnsupportedType is the same as unsupportedType, where seen holds the
+// types already checked so recursive types (eg: type T []T) terminate.
+func findUnsupportedType(typ reflect.Type, seen map[reflect.Type]bool) reflect.Type {
+	if seen[typ] {
+		return nil
+	}
+	seen[typ] = true
+
+	switch typ.Kind() {
+	case reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return typ
+	case reflect.Map:
+		if key := findUnsupportedType(typ.Key(), seen); key != nil {
+			return key
+		}
+		return findUnsupportedType(typ.Elem(), seen)
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		return findUnsupportedType(typ.Elem(), seen)
+	default:
+		return nil
+	}
 }
 
 func isString(targetType reflect.Type) bool {
@@ -391,6 +1750,28 @@ func isBytes(targetType reflect.Type) bool {
 	return targetType.Kind() == reflect.Slice && targetType.Elem().Kind() == reflect.Uint8
 }
 
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// isJSONUnmarshaler indicates a named []byte type which implements
+// json.Unmarshaler, meaning it should not be treated as raw contents. The
+// json.RawMessage type is excluded as it is still raw.
+func isJSONUnmarshaler(targetType reflect.Type) bool {
+	if targetType == rawMessageType || !isBytes(targetType) {
+		return false
+	}
+
+	return reflect.PointerTo(targetType).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem())
+}
+
+// isJSONMarshaler is the counterpart to isJSONUnmarshaler for saving.
+func isJSONMarshaler(targetType reflect.Type) bool {
+	if targetType == rawMessageType || !isBytes(targetType) {
+		return false
+	}
+
+	return targetType.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem())
+}
+
 func isMap(targetType reflect.Type) bool {
 	return targetType.Kind() == reflect.Map && isString(targetType.Key())
 }
@@ -0,0 +1,84 @@
+package got
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func BenchmarkLoadExplode(b *testing.B) {
+	type test struct {
+		Files map[string]string `testdata:"files/*.txt,explode"`
+	}
+
+	dir := b.TempDir()
+	require := func(err error) {
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	require(os.Mkdir(filepath.Join(dir, "files"), 0755))
+	for i := 0; i < 5000; i++ {
+		require(os.WriteFile(filepath.Join(dir, "files", fmt.Sprintf("%04d.txt", i)), []byte("hello world"), 0644))
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		var mt mockT
+		var actual test
+		Load(&mt, dir, &actual)
+
+		if mt.failed || len(actual.Files) != 5000 {
+			b.Fatal(mt.logs)
+		}
+	}
+}
+
+func BenchmarkLoadSharedFixture(b *testing.B) {
+	type test struct {
+		Fixture string `testdata:"fixture.txt"`
+		Input   string `testdata:"input.txt"`
+	}
+
+	dir := b.TempDir()
+	require := func(err error) {
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	shared := filepath.Join(dir, "shared")
+	require(os.Mkdir(shared, 0755))
+	require(os.WriteFile(filepath.Join(shared, "fixture.txt"), bytes.Repeat([]byte("hello world\n"), 1<<16), 0644))
+
+	var cases []string
+	for i := 0; i < 50; i++ {
+		c := filepath.Join(dir, fmt.Sprintf("case-%02d", i))
+		require(os.Mkdir(c, 0755))
+		require(os.WriteFile(filepath.Join(c, "input.txt"), []byte("hello world"), 0644))
+		cases = append(cases, c)
+	}
+
+	for _, opts := range []Options{{}, {CacheFiles: true}} {
+		b.Run(fmt.Sprintf("CacheFiles=%t", opts.CacheFiles), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				for _, c := range cases {
+					var mt mockT
+					var actual test
+					opts.LoadDirs(&mt, []string{shared, c}, &actual)
+
+					if mt.failed || actual.Fixture == "" {
+						b.Fatal(mt.logs)
+					}
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package got
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -9,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dominicbarnes/got/v2/codec"
 	"github.com/stretchr/testify/require"
 )
 
@@ -116,6 +118,26 @@ func TestLoad(t *testing.T) {
 		})
 	})
 
+	t.Run("json unmarshaler", func(t *testing.T) {
+		type test struct {
+			Input base64Bytes `testdata:"base64.json"`
+		}
+
+		testLoadOne(t, "json", new(test), &test{Input: base64Bytes("hello world")}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/json/base64.json" via UnmarshalJSON (size 18)`,
+		})
+	})
+
+	t.Run("json unmarshaler string", func(t *testing.T) {
+		type test struct {
+			Input quotedString `testdata:"input.txt"`
+		}
+
+		testLoadOne(t, "text", new(test), &test{Input: quotedString("hello world")}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/text/input.txt" as string (size 11)`,
+		})
+	})
+
 	t.Run("multiple", func(t *testing.T) {
 		type test struct {
 			A string `testdata:"a.txt"`
@@ -141,6 +163,26 @@ func TestLoad(t *testing.T) {
 			})
 		})
 
+		t.Run("non-string keys", func(t *testing.T) {
+			type test struct {
+				Input map[int]string `testdata:"intkeys.json"`
+			}
+
+			testLoadOne(t, "json", new(test), &test{
+				Input: map[int]string{1: "one", 2: "two"},
+			}, []string{
+				`[GoT] Load: *got.test.Input: loaded file "testdata/json/intkeys.json" as JSON (size 30)`,
+			})
+		})
+
+		t.Run("non-string keys explode", func(t *testing.T) {
+			type test struct {
+				Input map[int]string `testdata:"*.json,explode"`
+			}
+
+			testLoadError(t, "json", new(test), `[GoT] Load: *got.test.Input: explode requires a map with string keys, but got map[int]string`)
+		})
+
 		t.Run("expand glob", func(t *testing.T) {
 			type test struct {
 				Multiple map[string]string `testdata:"*.txt,explode"`
@@ -157,6 +199,169 @@ func TestLoad(t *testing.T) {
 			})
 		})
 
+		t.Run("glob matching a directory", func(t *testing.T) {
+			type test struct {
+				Multiple map[string]string `testdata:"*.txt,explode"`
+			}
+
+			testLoadOne(t, "explode-dirs", new(test), &test{
+				Multiple: map[string]string{
+					"a.txt": "A",
+					"b.txt": "B",
+				},
+			}, []string{
+				`[GoT] Load: *got.test.Multiple: skipped: "testdata/explode-dirs/c.txt" matches a directory, but explode only loads files`,
+				`[GoT] Load: *got.test.Multiple["a.txt"]: loaded file "testdata/explode-dirs/a.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Multiple["b.txt"]: loaded file "testdata/explode-dirs/b.txt" as string (size 1)`,
+			})
+		})
+
+		t.Run("glob matching a directory slice", func(t *testing.T) {
+			type test struct {
+				Multiple []string `testdata:"*.txt,explode"`
+			}
+
+			testLoadOne(t, "explode-dirs", new(test), &test{
+				Multiple: []string{"A", "B"},
+			}, []string{
+				`[GoT] Load: *got.test.Multiple: skipped: "testdata/explode-dirs/c.txt" matches a directory, but explode only loads files`,
+				`[GoT] Load: *got.test.Multiple[0]: loaded file "testdata/explode-dirs/a.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Multiple[1]: loaded file "testdata/explode-dirs/b.txt" as string (size 1)`,
+			})
+		})
+
+		t.Run("strip prefix", func(t *testing.T) {
+			type test struct {
+				Multiple map[string]string `testdata:"expected/*.txt,explode,strip=expected/"`
+			}
+
+			testLoadOne(t, "multiple-nested", new(test), &test{
+				Multiple: map[string]string{
+					"a.txt": "A",
+					"b.txt": "B",
+				},
+			}, []string{
+				`[GoT] Load: *got.test.Multiple["a.txt"]: loaded file "testdata/multiple-nested/expected/a.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Multiple["b.txt"]: loaded file "testdata/multiple-nested/expected/b.txt" as string (size 1)`,
+			})
+		})
+
+		t.Run("strip prefix without trailing slash", func(t *testing.T) {
+			type test struct {
+				Multiple map[string]string `testdata:"expected/*.txt,explode,strip=expected"`
+			}
+
+			testLoadOne(t, "multiple-nested", new(test), &test{
+				Multiple: map[string]string{
+					"a.txt": "A",
+					"b.txt": "B",
+				},
+			}, []string{
+				`[GoT] Load: *got.test.Multiple["a.txt"]: loaded file "testdata/multiple-nested/expected/a.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Multiple["b.txt"]: loaded file "testdata/multiple-nested/expected/b.txt" as string (size 1)`,
+			})
+		})
+
+		t.Run("strip prefix is a directory", func(t *testing.T) {
+			type test struct {
+				Multiple map[string]string `testdata:"*/a.txt,explode,strip=ex"`
+			}
+
+			testLoadError(t, "explode-strip-outside", new(test), `[GoT] Load: *got.test.Multiple: file testdata/explode-strip-outside/expected/a.txt is not within the strip directory "ex/"`)
+		})
+
+		t.Run("strip prefix outside directory", func(t *testing.T) {
+			type test struct {
+				Multiple map[string]string `testdata:"*/a.txt,explode,strip=expected/"`
+			}
+
+			testLoadError(t, "explode-strip-outside", new(test), `[GoT] Load: *got.test.Multiple: file testdata/explode-strip-outside/other/a.txt is not within the strip directory "expected/"`)
+		})
+
+		t.Run("recursive", func(t *testing.T) {
+			type test struct {
+				Tree map[string][]byte `testdata:"**,explode"`
+			}
+
+			testLoadOne(t, "tree", new(test), &test{
+				Tree: map[string][]byte{
+					"a.txt":          []byte("A"),
+					"sub/b.txt":      []byte("B"),
+					"sub/debug.log":  []byte("log"),
+					"sub/deep/c.txt": []byte("C"),
+				},
+			}, []string{
+				`[GoT] Load: *got.test.Tree["a.txt"]: loaded file "testdata/tree/a.txt" as bytes (size 1)`,
+				`[GoT] Load: *got.test.Tree["sub/b.txt"]: loaded file "testdata/tree/sub/b.txt" as bytes (size 1)`,
+				`[GoT] Load: *got.test.Tree["sub/debug.log"]: loaded file "testdata/tree/sub/debug.log" as bytes (size 3)`,
+				`[GoT] Load: *got.test.Tree["sub/deep/c.txt"]: loaded file "testdata/tree/sub/deep/c.txt" as bytes (size 1)`,
+			})
+		})
+
+		t.Run("recursive with exclude", func(t *testing.T) {
+			type test struct {
+				Tree map[string][]byte `testdata:"sub/**,explode,exclude=*.log|sub/deep/*"`
+			}
+
+			testLoadOne(t, "tree", new(test), &test{
+				Tree: map[string][]byte{
+					"sub/b.txt": []byte("B"),
+				},
+			}, []string{
+				`[GoT] Load: *got.test.Tree["sub/b.txt"]: loaded file "testdata/tree/sub/b.txt" as bytes (size 1)`,
+			})
+		})
+
+		t.Run("recursive missing dir", func(t *testing.T) {
+			type test struct {
+				Tree map[string][]byte `testdata:"missing/**,explode"`
+			}
+
+			testLoadOne(t, "tree", new(test), &test{}, []string{
+				`[GoT] Load: *got.test.Tree: no matches found`,
+			})
+		})
+
+		t.Run("recursive within pattern", func(t *testing.T) {
+			type test struct {
+				Tree map[string][]byte `testdata:"**/*.txt,explode"`
+			}
+
+			testLoadOne(t, "tree", new(test), &test{
+				Tree: map[string][]byte{
+					"a.txt":          []byte("A"),
+					"sub/b.txt":      []byte("B"),
+					"sub/deep/c.txt": []byte("C"),
+				},
+			}, []string{
+				`[GoT] Load: *got.test.Tree["a.txt"]: loaded file "testdata/tree/a.txt" as bytes (size 1)`,
+				`[GoT] Load: *got.test.Tree["sub/b.txt"]: loaded file "testdata/tree/sub/b.txt" as bytes (size 1)`,
+				`[GoT] Load: *got.test.Tree["sub/deep/c.txt"]: loaded file "testdata/tree/sub/deep/c.txt" as bytes (size 1)`,
+			})
+		})
+
+		t.Run("recursive within nested pattern", func(t *testing.T) {
+			type test struct {
+				Tree map[string][]byte `testdata:"sub/**/c.txt,explode"`
+			}
+
+			testLoadOne(t, "tree", new(test), &test{
+				Tree: map[string][]byte{
+					"sub/deep/c.txt": []byte("C"),
+				},
+			}, []string{
+				`[GoT] Load: *got.test.Tree["sub/deep/c.txt"]: loaded file "testdata/tree/sub/deep/c.txt" as bytes (size 1)`,
+			})
+		})
+
+		t.Run("recursive within segment", func(t *testing.T) {
+			type test struct {
+				Tree map[string][]byte `testdata:"sub**/*.txt,explode"`
+			}
+
+			testLoadError(t, "tree", new(test), `[GoT] Load: *got.test.Tree: failed to list files testdata/tree/sub**/*.txt: ** must be an entire path segment, but got "sub**"`)
+		})
+
 		t.Run("single file", func(t *testing.T) {
 			type test struct {
 				Multiple map[string]string `testdata:"a.txt,explode"`
@@ -197,6 +402,32 @@ func TestLoad(t *testing.T) {
 			})
 		})
 
+		t.Run("glob without matches empty", func(t *testing.T) {
+			type test struct {
+				Multiple map[string]string `testdata:"*.log,explode,empty"`
+			}
+
+			testLoadOne(t, "multiple", new(test), &test{
+				Multiple: map[string]string{},
+			}, []string{
+				`[GoT] Load: *got.test.Multiple: no matches found, using an empty map`,
+			})
+		})
+
+		t.Run("glob without matches empty assert", func(t *testing.T) {
+			type test struct {
+				Multiple map[string]string `testdata:"*.log,explode,empty"`
+			}
+
+			var mt mockT
+			Assert(&mt, "testdata/multiple", &test{Multiple: map[string]string{}})
+			require.False(t, mt.failed, mt.logs)
+
+			mt = mockT{}
+			Assert(&mt, "testdata/multiple", &test{})
+			require.True(t, mt.failed)
+		})
+
 		t.Run("glob nested", func(t *testing.T) {
 			type test struct {
 				Input    []string          `testdata:"input.json"`
@@ -217,6 +448,156 @@ func TestLoad(t *testing.T) {
 		})
 	})
 
+	t.Run("description", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.txt,desc=greeting message"`
+		}
+
+		testLoadOne(t, "text", new(test), &test{Input: "hello world"}, []string{
+			`[GoT] Load: *got.test.Input (greeting message): loaded file "testdata/text/input.txt" as string (size 11)`,
+		})
+	})
+
+	t.Run("description error", func(t *testing.T) {
+		type test struct {
+			Input struct{ Hello string } `testdata:"input.unknown,desc=unknown format"`
+		}
+
+		testLoadError(t, "unknown", new(test), `[GoT] Load: *got.test.Input (unknown format): failed to get codec for file extension ".unknown"`)
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		type test struct {
+			Input  string `testdata:"input.txt"`
+			Output string `testdata:"output.txt"`
+		}
+
+		testLoadOne(t, "manifest", new(test), &test{Input: "alternate"}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/manifest/alternate.txt" as string (size 9)`,
+			`[GoT] Load: *got.test.Output: skipped: file "testdata/manifest/output.txt" not found`,
+		})
+	})
+
+	t.Run("manifest multiple values", func(t *testing.T) {
+		type in struct {
+			Input string `testdata:"input.txt"`
+		}
+
+		type out struct {
+			Output string `testdata:"output.txt"`
+		}
+
+		var mt mockT
+		var input in
+		var output out
+		Load(&mt, "testdata/manifest", &input, &output)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, in{Input: "alternate"}, input)
+	})
+
+	t.Run("manifest unknown field", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.txt"`
+		}
+
+		testLoadError(t, "manifest-unknown", new(test), `[GoT] Load: manifest testdata/manifest-unknown/manifest.json references unknown field *got.test.Missing`)
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		type test struct {
+			Sequence chan int `testdata:"sequence.json"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/json", &actual)
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			logs: []string{
+				`[GoT] Load: *got.test.Sequence: loaded file "testdata/json/sequence.json" as JSON into channel (size 10)`,
+			},
+		}, mt)
+
+		var items []int
+		for item := range actual.Sequence {
+			items = append(items, item)
+		}
+		require.Equal(t, []int{1, 2, 3}, items)
+	})
+
+	t.Run("receive-only channel", func(t *testing.T) {
+		type test struct {
+			Sequence <-chan int `testdata:"sequence.json"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/json", &actual)
+		require.False(t, mt.failed, mt.logs)
+
+		var items []int
+		for item := range actual.Sequence {
+			items = append(items, item)
+		}
+		require.Equal(t, []int{1, 2, 3}, items)
+	})
+
+	t.Run("map of raw messages", func(t *testing.T) {
+		type test struct {
+			Input map[string]json.RawMessage `testdata:"input.json"`
+		}
+
+		testLoadOne(t, "rawmessage", new(test), &test{
+			Input: map[string]json.RawMessage{
+				"string": json.RawMessage(`"hello"`),
+				"number": json.RawMessage(`42`),
+				"object": json.RawMessage(`{"a": [1, 2]}`),
+			},
+		}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/rawmessage/input.json" as JSON (size 67)`,
+		})
+	})
+
+	t.Run("map precedence", func(t *testing.T) {
+		type request struct {
+			Method string `json:"method"`
+			Path   string `json:"path"`
+		}
+
+		t.Run("without explode", func(t *testing.T) {
+			type test struct {
+				Requests map[string]request `testdata:"requests.json"`
+			}
+
+			testLoadOne(t, "map-precedence", new(test), &test{
+				Requests: map[string]request{
+					"login":  {Method: "POST", Path: "/login"},
+					"logout": {Method: "POST", Path: "/logout"},
+				},
+			}, []string{
+				`[GoT] Load: *got.test.Requests: loaded file "testdata/map-precedence/requests.json" as JSON (size 103)`,
+			})
+		})
+
+		t.Run("with explode", func(t *testing.T) {
+			type test struct {
+				Requests map[string]request `testdata:"requests/*.json,explode"`
+			}
+
+			testLoadOne(t, "map-precedence", new(test), &test{
+				Requests: map[string]request{
+					"requests/login.json":  {Method: "GET", Path: "/login"},
+					"requests/logout.json": {Method: "DELETE", Path: "/logout"},
+				},
+			}, []string{
+				`[GoT] Load: *got.test.Requests["requests/login.json"]: loaded file "testdata/map-precedence/requests/login.json" as JSON (size 36)`,
+				`[GoT] Load: *got.test.Requests["requests/logout.json"]: loaded file "testdata/map-precedence/requests/logout.json" as JSON (size 40)`,
+			})
+		})
+	})
+
 	t.Run("json codec", func(t *testing.T) {
 		type JSONInput struct {
 			Hello string `json:"hello"`
@@ -273,6 +654,48 @@ func TestLoad(t *testing.T) {
 		})
 	})
 
+	t.Run("codec describer", func(t *testing.T) {
+		registerDescribedCodec(t)
+
+		type test struct {
+			Input map[string]string `testdata:"input.described"`
+		}
+
+		testLoadOne(t, "described", new(test), &test{
+			Input: map[string]string{"hello": "world"},
+		}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/described/input.described" as JSON indent="  " (size 18)`,
+		})
+	})
+
+	t.Run("codec layer", func(t *testing.T) {
+		type test struct {
+			Input map[string]string `testdata:"input.json.b64"`
+		}
+
+		testLoadOne(t, "base64", new(test), &test{
+			Input: map[string]string{"a": "hello", "b": "world"},
+		}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/base64/input.json.b64" as JSON+base64 (size 37)`,
+		})
+	})
+
+	t.Run("codec layer decode error", func(t *testing.T) {
+		type test struct {
+			Input map[string]string `testdata:"invalid.json.b64"`
+		}
+
+		testLoadError(t, "base64", new(test), `[GoT] Load: *got.test.Input: file "testdata/base64/invalid.json.b64" decode error: base64: illegal base64 data at input byte 3`)
+	})
+
+	t.Run("codec layer unknown inner codec", func(t *testing.T) {
+		type test struct {
+			Input map[string]string `testdata:"input.unknown.b64"`
+		}
+
+		testLoadError(t, "base64", new(test), `[GoT] Load: *got.test.Input: failed to get codec for file extension ".unknown"`)
+	})
+
 	t.Run("unknown codec", func(t *testing.T) {
 		type test struct {
 			Input struct{ Hello string } `testdata:"input.unknown"`
@@ -340,24 +763,125 @@ func TestLoadDirs(t *testing.T) {
 		}, mt)
 	})
 
-	t.Run("missing arguments", func(t *testing.T) {
+	t.Run("explode merge", func(t *testing.T) {
+		type test struct {
+			Files map[string]string `testdata:"expected/*.txt,explode"`
+		}
+
 		var mt mockT
-		LoadDirs(&mt, []string{"testdata/multiple-dirs/dir1", "testdata/multiple-dirs/dir2"})
+		var actual test
+		LoadDirs(&mt, []string{"testdata/multiple-dirs-explode/dir1", "testdata/multiple-dirs-explode/dir2"}, &actual)
+
+		require.EqualValues(t, test{
+			Files: map[string]string{
+				"expected/a.txt": "A",
+				"expected/b.txt": "b",
+				"expected/c.txt": "C",
+			},
+		}, actual)
 
 		require.EqualValues(t, mockT{
 			helper: true,
-			failed: true,
 			logs: []string{
-				"[GoT] LoadDirs: at least 1 output required",
+				`[GoT] Load: *got.test.Files["expected/a.txt"]: loaded file "testdata/multiple-dirs-explode/dir1/expected/a.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Files["expected/b.txt"]: loaded file "testdata/multiple-dirs-explode/dir1/expected/b.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Files["expected/b.txt"]: loaded file "testdata/multiple-dirs-explode/dir2/expected/b.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Files["expected/c.txt"]: loaded file "testdata/multiple-dirs-explode/dir2/expected/c.txt" as string (size 1)`,
 			},
 		}, mt)
 	})
-}
 
-func TestAssert(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
+	t.Run("explode merge without matches", func(t *testing.T) {
 		type test struct {
-			Input string `testdata:"input.txt"`
+			Files map[string]string `testdata:"expected/a.txt,explode"`
+		}
+
+		var mt mockT
+		var actual test
+		LoadDirs(&mt, []string{"testdata/multiple-dirs-explode/dir1", "testdata/multiple-dirs-explode/dir2"}, &actual)
+
+		require.EqualValues(t, test{
+			Files: map[string]string{"expected/a.txt": "A"},
+		}, actual)
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			logs: []string{
+				`[GoT] Load: *got.test.Files["expected/a.txt"]: loaded file "testdata/multiple-dirs-explode/dir1/expected/a.txt" as string (size 1)`,
+				`[GoT] Load: *got.test.Files: no matches found`,
+			},
+		}, mt)
+	})
+
+	t.Run("missing arguments", func(t *testing.T) {
+		var mt mockT
+		LoadDirs(&mt, []string{"testdata/multiple-dirs/dir1", "testdata/multiple-dirs/dir2"})
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"[GoT] LoadDirs: at least 1 output required",
+			},
+		}, mt)
+	})
+}
+
+func TestLoadT(t *testing.T) {
+	type test struct {
+		A string `testdata:"a.txt"`
+		B []byte `testdata:"b.txt"`
+	}
+
+	var mt mockT
+	actual := LoadT[test](&mt, "testdata/multiple")
+
+	require.EqualValues(t, test{A: "A", B: []byte("B")}, actual)
+	require.EqualValues(t, mockT{
+		helper: true,
+		logs: []string{
+			`[GoT] Load: *got.test.A: loaded file "testdata/multiple/a.txt" as string (size 1)`,
+			`[GoT] Load: *got.test.B: loaded file "testdata/multiple/b.txt" as bytes (size 1)`,
+		},
+	}, mt)
+}
+
+func TestLoadOnly(t *testing.T) {
+	type test struct {
+		A string `testdata:"a.txt"`
+		B string `testdata:"b.txt"`
+	}
+
+	t.Run("subset", func(t *testing.T) {
+		var mt mockT
+		var actual test
+		LoadOnly(&mt, "testdata/multiple", []string{"B"}, &actual)
+
+		require.EqualValues(t, test{B: "B"}, actual)
+		require.EqualValues(t, mockT{
+			helper: true,
+			logs: []string{
+				`[GoT] Load: *got.test.B: loaded file "testdata/multiple/b.txt" as string (size 1)`,
+			},
+		}, mt)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		var mt mockT
+		LoadOnly(&mt, "testdata/multiple", []string{"C"}, new(test))
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs:   []string{"[GoT] Load: unknown field *got.test.C"},
+		}, mt)
+	})
+}
+
+func TestAssert(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.txt"`
 		}
 
 		var mt mockT
@@ -386,6 +910,95 @@ func TestAssert(t *testing.T) {
 		require.True(t, strings.HasPrefix(mt.logs[1], "[GoT] Assert: test of *got.test failed:"))
 	})
 
+	t.Run("channel", func(t *testing.T) {
+		type test struct {
+			Sequence chan int `testdata:"sequence.json"`
+		}
+
+		var mt mockT
+		Assert(&mt, "testdata/json", &test{Sequence: make(chan int)})
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"[GoT] Assert: *got.test.Sequence: channel fields can only be loaded, not asserted",
+			},
+		}, mt)
+	})
+
+	t.Run("map of raw messages", func(t *testing.T) {
+		type test struct {
+			Input map[string]json.RawMessage `testdata:"input.json,jsoneq"`
+		}
+
+		value := &test{
+			Input: map[string]json.RawMessage{
+				"string": json.RawMessage(`"hello"`),
+				"number": json.RawMessage(`42`),
+				"object": json.RawMessage(`{"a": [1, 2]}`),
+			},
+		}
+
+		// existing fixture with different whitespace
+		var mt mockT
+		Assert(&mt, "testdata/rawmessage", value)
+		require.False(t, mt.failed, mt.logs)
+
+		// round trip, where the raw values are re-indented when saved
+		dir := t.TempDir()
+
+		updateGolden = true
+		Assert(&mt, dir, value)
+		updateGolden = false
+		require.False(t, mt.failed, mt.logs)
+
+		Assert(&mt, dir, value)
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("map of raw messages without jsoneq", func(t *testing.T) {
+		type test struct {
+			Input map[string]json.RawMessage `testdata:"input.json"`
+		}
+
+		// existing fixture with different whitespace
+		var mt mockT
+		Assert(&mt, "testdata/rawmessage", &test{
+			Input: map[string]json.RawMessage{
+				"string": json.RawMessage(`"hello"`),
+				"number": json.RawMessage(`42`),
+				"object": json.RawMessage(`{"a":[1,2]}`),
+			},
+		})
+		require.True(t, mt.failed)
+	})
+
+	t.Run("unexported fields", func(t *testing.T) {
+		type nested struct {
+			Hello string `json:"hello"`
+			cache map[string]string
+		}
+
+		type test struct {
+			Input  nested `testdata:"input.json,ignoreunexported"`
+			loaded bool
+		}
+
+		var mt mockT
+		Assert(&mt, "testdata/json", &test{
+			Input:  nested{Hello: "world", cache: map[string]string{"a": "b"}},
+			loaded: true,
+		})
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			logs: []string{
+				`[GoT] Assert: *got.test.Input: loaded file "testdata/json/input.json" as JSON (size 22)`,
+			},
+		}, mt)
+	})
+
 	t.Run("missing arguments", func(t *testing.T) {
 		var mt mockT
 		Assert(&mt, "testdata/text")
@@ -399,7 +1012,36 @@ func TestAssert(t *testing.T) {
 		}, mt)
 	})
 
+	t.Run("update locked", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"input.txt"`
+		}
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		t.Setenv("GOT_NO_UPDATE", "1")
+
+		dir := t.TempDir()
+
+		var mt mockT
+		Assert(&mt, dir, &test{Input: "hello world"})
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"[GoT] Assert: golden files cannot be updated while GOT_NO_UPDATE is set",
+			},
+		}, mt)
+
+		_, err := os.Stat(filepath.Join(dir, "input.txt"))
+		require.True(t, os.IsNotExist(err))
+	})
+
 	t.Run("update", func(t *testing.T) {
+		registerDescribedCodec(t)
+
 		spec := []struct {
 			name     string
 			expected any
@@ -439,6 +1081,17 @@ func TestAssert(t *testing.T) {
 					`[GoT] Assert: <anonymous>.Input: saved file "<tmp>/input.json" (size 2)`,
 				},
 			},
+			{
+				name: "json marshaler",
+				expected: &struct {
+					Input base64Bytes `testdata:"input.json"`
+				}{
+					Input: base64Bytes("hello world"),
+				},
+				logs: []string{
+					`[GoT] Assert: <anonymous>.Input: saved file "<tmp>/input.json" (size 18)`,
+				},
+			},
 			{
 				name: "json struct",
 				expected: &struct {
@@ -479,6 +1132,83 @@ func TestAssert(t *testing.T) {
 					`[GoT] Assert: <anonymous>.Files: saved file "<tmp>/b.txt" (size 1)`,
 				},
 			},
+			{
+				name: "map explode strip",
+				expected: &struct {
+					Files map[string]string `testdata:"expected/*.txt,explode,strip=expected/"`
+				}{
+					Files: map[string]string{"a.txt": "A", "b.txt": "B"},
+				},
+				logs: []string{
+					`[GoT] Assert: <anonymous>.Files: saved file "<tmp>/expected/a.txt" (size 1)`,
+					`[GoT] Assert: <anonymous>.Files: saved file "<tmp>/expected/b.txt" (size 1)`,
+				},
+			},
+			{
+				name: "codec describer",
+				expected: &struct {
+					Input map[string]string `testdata:"input.described"`
+				}{
+					Input: map[string]string{"hello": "world"},
+				},
+				logs: []string{
+					`[GoT] Assert: <anonymous>.Input: saved file "<tmp>/input.described" as JSON indent="  " (size 22)`,
+				},
+			},
+			{
+				name: "description",
+				expected: &struct {
+					Input string `testdata:"input.txt,desc=greeting message"`
+				}{
+					Input: "hello world",
+				},
+				logs: []string{
+					`[GoT] Assert: <anonymous>.Input (greeting message): saved file "<tmp>/input.txt" (size 11)`,
+				},
+			},
+			{
+				name: "codec layer",
+				expected: &struct {
+					Input map[string]string `testdata:"input.json.b64"`
+				}{
+					Input: map[string]string{"hello": "world"},
+				},
+				logs: []string{
+					`[GoT] Assert: <anonymous>.Input: saved file "<tmp>/input.json.b64" as JSON+base64 (size 32)`,
+				},
+			},
+			{
+				name: "codec layer indent",
+				expected: &struct {
+					Input map[string]string `testdata:"input.json.b64,indent=0"`
+				}{
+					Input: map[string]string{"hello": "world"},
+				},
+				logs: []string{
+					`[GoT] Assert: <anonymous>.Input: saved file "<tmp>/input.json.b64" as JSON+base64 (size 24)`,
+				},
+			},
+			{
+				name: "map explode non-string keys",
+				expected: &struct {
+					Files map[int]string `testdata:"*.txt,explode"`
+				}{
+					Files: map[int]string{1: "A"},
+				},
+				fail: true,
+			},
+			{
+				name: "map explode recursive",
+				expected: &struct {
+					Tree map[string][]byte `testdata:"**,explode"`
+				}{
+					Tree: map[string][]byte{"a.txt": []byte("A"), "sub/deep/b.txt": []byte("B")},
+				},
+				logs: []string{
+					`[GoT] Assert: <anonymous>.Tree: saved file "<tmp>/a.txt" (size 1)`,
+					`[GoT] Assert: <anonymous>.Tree: saved file "<tmp>/sub/deep/b.txt" (size 1)`,
+				},
+			},
 			{
 				name: "unknown codec",
 				expected: &struct {
@@ -585,6 +1315,372 @@ func TestAssert(t *testing.T) {
 	})
 }
 
+// base64Bytes is decoded from a base64 JSON string rather than raw contents.
+type base64Bytes []byte
+
+func (b base64Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]byte(b))
+}
+
+func (b *base64Bytes) UnmarshalJSON(data []byte) error {
+	var raw []byte
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
+
+// quotedString implements the JSON interfaces, but is still loaded as raw text
+// since only named []byte types are decoded using them.
+type quotedString string
+
+func (s quotedString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+func (s *quotedString) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*string)(s))
+}
+
+// registerDescribedCodec registers describedCodec for ".described" until the
+// test has finished.
+func registerDescribedCodec(t *testing.T) {
+	t.Cleanup(codec.Snapshot())
+	codec.Register(".described", &describedCodec{JSONCodec: codec.JSONCodec{Indent: "  "}})
+}
+
+type describedCodec struct {
+	codec.JSONCodec
+}
+
+func (c *describedCodec) Describe() string {
+	return fmt.Sprintf("%s indent=%q", c.Name(), c.Indent)
+}
+
+func TestAssertDefaultIndent(t *testing.T) {
+	type test struct {
+		Output map[string]string `testdata:"output.json"`
+	}
+
+	t.Cleanup(codec.Snapshot())
+	require.NoError(t, codec.SetDefaultIndent(".json", 4))
+
+	updateGolden = true
+	t.Cleanup(func() { updateGolden = false })
+
+	dir := t.TempDir()
+
+	var mt mockT
+	Assert(&mt, dir, &test{Output: map[string]string{"hello": "world"}})
+	require.False(t, mt.failed, mt.logs)
+
+	data, err := os.ReadFile(filepath.Join(dir, "output.json"))
+	require.NoError(t, err)
+	require.Equal(t, "{\n    \"hello\": \"world\"\n}", string(data))
+}
+
+func TestCodecOption(t *testing.T) {
+	type object struct {
+		Name string `json:"name" yaml:"name"`
+	}
+
+	t.Run("load", func(t *testing.T) {
+		type test struct {
+			Text object `testdata:"data.txt,codec=json"`
+			JSON object `testdata:"data.json,codec=yaml"`
+		}
+
+		testLoadOne(t, "codec-option", new(test), &test{
+			Text: object{Name: "json in txt"},
+			JSON: object{Name: "yaml in json"},
+		}, []string{
+			`[GoT] Load: *got.test.Text: loaded file "testdata/codec-option/data.txt" as JSON (size 24)`,
+			`[GoT] Load: *got.test.JSON: loaded file "testdata/codec-option/data.json" as YAML (size 19)`,
+		})
+	})
+
+	t.Run("save", func(t *testing.T) {
+		type test struct {
+			Text object `testdata:"data.txt,codec=json,indent=0"`
+		}
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+
+		var mt mockT
+		Assert(&mt, dir, &test{Text: object{Name: "saved"}})
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "data.txt"))
+		require.NoError(t, err)
+		require.Equal(t, `{"name":"saved"}`, string(data))
+	})
+
+	t.Run("precedence over resolver", func(t *testing.T) {
+		type test struct {
+			Text object `testdata:"data.txt,codec=json"`
+		}
+
+		o := Options{
+			CodecResolver: func(string, reflect.StructField) (codec.Codec, bool) {
+				return new(codec.YAMLCodec), true
+			},
+		}
+
+		var mt mockT
+		var actual test
+		o.Load(&mt, "testdata/codec-option", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, "json in txt", actual.Text.Name)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		type test struct {
+			Text object `testdata:"data.txt,codec=nope"`
+		}
+
+		testLoadError(t, "codec-option", new(test), `[GoT] Load: *got.test.Text: no codec named "nope" has been registered`)
+	})
+}
+
+func TestAssertFieldIndent(t *testing.T) {
+	type test struct {
+		Default map[string]string `testdata:"default.json"`
+		Custom  map[string]string `testdata:"custom.json,indent=4"`
+	}
+
+	updateGolden = true
+	t.Cleanup(func() { updateGolden = false })
+
+	dir := t.TempDir()
+
+	var mt mockT
+	Assert(&mt, dir, &test{
+		Default: map[string]string{"hello": "world"},
+		Custom:  map[string]string{"hello": "world"},
+	})
+	require.False(t, mt.failed, mt.logs)
+
+	data, err := os.ReadFile(filepath.Join(dir, "default.json"))
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"hello\": \"world\"\n}", string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "custom.json"))
+	require.NoError(t, err)
+	require.Equal(t, "{\n    \"hello\": \"world\"\n}", string(data))
+
+	t.Run("unsupported codec", func(t *testing.T) {
+		type test struct {
+			Output map[string]string `testdata:"output.cbor,indent=4"`
+		}
+
+		var mt mockT
+		Assert(&mt, t.TempDir(), &test{Output: map[string]string{"hello": "world"}})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "codec CBOR does not support indentation")
+	})
+}
+
+func TestLoadUnsupportedType(t *testing.T) {
+	t.Run("func", func(t *testing.T) {
+		type test struct {
+			Fn func() `testdata:"input.txt"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/text", new(test))
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				"[GoT] Load: *got.test.Fn: unsupported type func()",
+			},
+		}, mt)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		type test struct {
+			Values map[string][]complex128 `testdata:"*.txt,explode"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/text", new(test))
+
+		require.EqualValues(t, []string{
+			"[GoT] Load: *got.test.Values: unsupported type complex128",
+		}, mt.logs)
+	})
+
+	t.Run("map key", func(t *testing.T) {
+		type test struct {
+			Values map[complex64]string `testdata:"input.json"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/json", new(test))
+
+		require.EqualValues(t, []string{
+			"[GoT] Load: *got.test.Values: unsupported type complex64",
+		}, mt.logs)
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		type tree []tree
+
+		require.Nil(t, unsupportedType(reflect.TypeOf(tree(nil))))
+	})
+
+	t.Run("uintptr", func(t *testing.T) {
+		type test struct {
+			Input map[string]uintptr `testdata:"input.json"`
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "input.json"), []byte(`{"a": 1}`), 0644))
+
+		var mt mockT
+		var actual test
+		Load(&mt, dir, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, map[string]uintptr{"a": 1}, actual.Input)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		type test struct {
+			Fn func() `testdata:"input.txt"`
+		}
+
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		var mt mockT
+		Assert(&mt, t.TempDir(), &test{Fn: func() {}})
+
+		require.EqualValues(t, []string{
+			"[GoT] Assert: *got.test.Fn error: unsupported type func()",
+		}, mt.logs)
+	})
+}
+
+func TestLoadNonEmpty(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"empty.txt,nonempty"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/nonempty", new(test))
+
+		require.EqualValues(t, mockT{
+			helper: true,
+			failed: true,
+			logs: []string{
+				`[GoT] Load: *got.test.Input: file "testdata/nonempty/empty.txt" is empty, but the nonempty option requires contents`,
+			},
+		}, mt)
+	})
+
+	t.Run("empty cached", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"empty.txt,nonempty"`
+		}
+
+		var mt mockT
+		Options{CacheFiles: true}.Load(&mt, "testdata/nonempty", new(test))
+
+		require.True(t, mt.failed)
+	})
+
+	t.Run("empty without option", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"empty.txt"`
+		}
+
+		testLoadOne(t, "nonempty", new(test), &test{}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/nonempty/empty.txt" as string (size 0)`,
+		})
+	})
+
+	t.Run("not empty", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"full.txt,nonempty"`
+		}
+
+		testLoadOne(t, "nonempty", new(test), &test{Input: "hello"}, []string{
+			`[GoT] Load: *got.test.Input: loaded file "testdata/nonempty/full.txt" as string (size 5)`,
+		})
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		type test struct {
+			Input string `testdata:"missing.txt,nonempty"`
+		}
+
+		testLoadOne(t, "nonempty", new(test), &test{}, []string{
+			`[GoT] Load: *got.test.Input: skipped: file "testdata/nonempty/missing.txt" not found`,
+		})
+	})
+}
+
+func TestLoadLimit(t *testing.T) {
+	t.Run("slice", func(t *testing.T) {
+		type test struct {
+			Items []int `testdata:"items.json,limit=5"`
+		}
+
+		testLoadOne(t, "limit", new(test), &test{Items: []int{1, 2, 3, 4, 5}}, []string{
+			`[GoT] Load: *got.test.Items: loaded file "testdata/limit/items.json" as JSON (size 393)`,
+			`[GoT] Load: *got.test.Items: limited to 5 of 100 elements`,
+		})
+	})
+
+	t.Run("under limit", func(t *testing.T) {
+		type test struct {
+			Items []int `testdata:"items.json,limit=500"`
+		}
+
+		var mt mockT
+		var actual test
+		Load(&mt, "testdata/limit", &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Len(t, actual.Items, 100)
+	})
+
+	t.Run("not a slice", func(t *testing.T) {
+		type test struct {
+			Items map[string]int `testdata:"items.json,limit=5"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/limit", new(test))
+
+		require.EqualValues(t, []string{
+			"[GoT] Load: *got.test.Items: limit requires a slice, but got map[string]int",
+		}, mt.logs)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		type test struct {
+			Items []int `testdata:"items.json,limit=none"`
+		}
+
+		var mt mockT
+		Load(&mt, "testdata/limit", new(test))
+
+		require.EqualValues(t, []string{
+			`[GoT] Load: *got.test.Items: invalid limit "none"`,
+		}, mt.logs)
+	})
+}
+
 func testLoadOne(t *testing.T, input string, output, expected any, logs []string) {
 	t.Helper()
 
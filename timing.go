@@ -0,0 +1,38 @@
+package got
+
+import "time"
+
+// fileTiming records how long each step of loading a file took, as used by
+// Options.Timing. The methods do nothing for a nil *fileTiming, so the steps
+// can be recorded unconditionally.
+type fileTiming struct {
+	open   time.Duration
+	read   time.Duration
+	decode time.Duration
+}
+
+func (t *fileTiming) setOpen(d time.Duration) {
+	if t != nil {
+		t.open = d
+	}
+}
+
+func (t *fileTiming) setRead(d time.Duration) {
+	if t != nil {
+		t.read = d
+	}
+}
+
+func (t *fileTiming) setDecode(d time.Duration) {
+	if t != nil {
+		t.decode = d
+	}
+}
+
+// log reports the timing for file, where the open and read steps are zero when
+// the contents were reused from the cache (see Options.CacheFiles).
+func (t *fileTiming) log(log *logger, file string) {
+	if t != nil {
+		log.Log("timing for file %q: open %s, read %s, decode %s", file, t.open, t.read, t.decode)
+	}
+}
@@ -0,0 +1,161 @@
+package got
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/fatih/structtag"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// The "unordered" option treats a slice as a set, so Assert ignores the order
+// of the elements when comparing, while saving them in a canonical sorted order
+// (eg: `testdata:"tags.json,unordered"`). Fields using the "explode" option
+// apply this to the slice in each of the files.
+const unorderedOption = "unordered"
+
+// unorderedOptions returns the cmp options which ignore the order of the slice
+// fields of v (a pointer to a struct) using the "unordered" option.
+func unorderedOptions(v any) []cmp.Option {
+	if v == nil || reflect.TypeOf(v).Kind() != reflect.Ptr || reflect.TypeOf(v).Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	typ := reflect.TypeOf(v).Elem()
+
+	var opts []cmp.Option
+	_ = walkFields(reflect.New(typ).Interface(), func(field reflect.StructField, _ reflect.Value, tag *structtag.Tag) error {
+		if !tag.HasOption(unorderedOption) {
+			return nil
+		}
+
+		slice, err := unorderedSliceType(field, tag)
+		if err != nil {
+			return nil // invalid fields are reported by sortUnordered
+		}
+
+		name := field.Name
+		opts = append(opts, cmp.FilterPath(func(p cmp.Path) bool {
+			for i := 1; i < len(p); i++ {
+				if sf, ok := p[i].(cmp.StructField); ok && sf.Name() == name && p[i-1].Type() == typ {
+					return true
+				}
+			}
+			return false
+		}, cmpopts.SortSlices(lessFunc(slice.Elem()))))
+
+		return nil
+	})
+
+	return opts
+}
+
+// sortUnordered returns a shallow copy of input (a pointer to a struct) where
+// the slice fields using the "unordered" option are replaced with sorted
+// copies, so they are saved in a canonical order.
+func sortUnordered(input any) (any, error) {
+	if input == nil || reflect.TypeOf(input).Kind() != reflect.Ptr || reflect.TypeOf(input).Elem().Kind() != reflect.Struct {
+		return input, nil // invalid inputs are reported elsewhere
+	}
+
+	typ := reflect.TypeOf(input).Elem()
+	output := reflect.New(typ)
+	output.Elem().Set(reflect.ValueOf(input).Elem())
+
+	err := walkFields(output.Interface(), func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		if !tag.HasOption(unorderedOption) {
+			return nil
+		}
+
+		if _, err := unorderedSliceType(field, tag); err != nil {
+			return fmt.Errorf("%s.%s: %w", getTypeName(input), fieldName(field, tag), err)
+		}
+
+		if value.IsZero() {
+			return nil
+		}
+
+		if isMap(field.Type) {
+			m := reflect.MakeMapWithSize(field.Type, value.Len())
+			for _, key := range value.MapKeys() {
+				m.SetMapIndex(key, sortedSlice(value.MapIndex(key)))
+			}
+			value.Set(m)
+			return nil
+		}
+
+		value.Set(sortedSlice(value))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Interface(), nil
+}
+
+// unorderedSliceType returns the slice type which the "unordered" option
+// applies to for field.
+func unorderedSliceType(field reflect.StructField, tag *structtag.Tag) (reflect.Type, error) {
+	typ := field.Type
+	if isMap(typ) && tag.HasOption("explode") {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Slice || isBytes(typ) {
+		return nil, fmt.Errorf("unordered requires a slice, but got %s", typ)
+	}
+
+	return typ, nil
+}
+
+// sortedSlice returns a sorted copy of slice, leaving the original untouched.
+func sortedSlice(slice reflect.Value) reflect.Value {
+	if slice.IsNil() {
+		return slice
+	}
+
+	sorted := reflect.MakeSlice(slice.Type(), slice.Len(), slice.Len())
+	reflect.Copy(sorted, slice)
+
+	sort.SliceStable(sorted.Interface(), func(i, j int) bool {
+		return lessValues(sorted.Index(i), sorted.Index(j))
+	})
+
+	return sorted
+}
+
+// lessFunc returns a func(T, T) bool for the element type typ, as required by
+// cmpopts.SortSlices.
+func lessFunc(typ reflect.Type) any {
+	fn := reflect.FuncOf([]reflect.Type{typ, typ}, []reflect.Type{reflect.TypeOf(true)}, false)
+
+	return reflect.MakeFunc(fn, func(args []reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.ValueOf(lessValues(args[0], args[1]))}
+	}).Interface()
+}
+
+// lessValues orders basic types naturally, while any other types are ordered
+// by their JSON encoding, which is deterministic (eg: map keys are sorted).
+func lessValues(a, b reflect.Value) bool {
+	switch {
+	case a.Kind() == reflect.String:
+		return a.String() < b.String()
+	case a.CanInt():
+		return a.Int() < b.Int()
+	case a.CanUint():
+		return a.Uint() < b.Uint()
+	case a.CanFloat():
+		return a.Float() < b.Float()
+	case a.Kind() == reflect.Bool:
+		return !a.Bool() && b.Bool()
+	}
+
+	dataA, _ := json.Marshal(a.Interface())
+	dataB, _ := json.Marshal(b.Interface())
+	return bytes.Compare(dataA, dataB) < 0
+}
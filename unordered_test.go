@@ -0,0 +1,92 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnordered(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	type test struct {
+		Tags  []string `testdata:"tags.json,unordered"`
+		Users []user   `testdata:"users.json,unordered"`
+	}
+
+	t.Run("permutation", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "testdata/unordered", &test{
+			Tags:  []string{"c", "a", "b"},
+			Users: []user{{Name: "bob"}, {Name: "alice"}},
+		})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("different elements", func(t *testing.T) {
+		var mt mockT
+		Assert(&mt, "testdata/unordered", &test{
+			Tags:  []string{"c", "a", "d"},
+			Users: []user{{Name: "bob"}, {Name: "alice"}},
+		})
+
+		require.True(t, mt.failed)
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		type test struct {
+			Tags []string `testdata:"tags.json"`
+		}
+
+		var mt mockT
+		Assert(&mt, "testdata/unordered", &test{Tags: []string{"c", "a", "b"}})
+
+		require.True(t, mt.failed)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		updateGolden = true
+		t.Cleanup(func() { updateGolden = false })
+
+		dir := t.TempDir()
+		actual := &test{
+			Tags:  []string{"c", "a", "b"},
+			Users: []user{{Name: "bob"}, {Name: "alice"}},
+		}
+
+		var mt mockT
+		Assert(&mt, dir, actual)
+		require.False(t, mt.failed, mt.logs)
+
+		for _, file := range []string{"tags.json", "users.json"} {
+			data, err := os.ReadFile(filepath.Join(dir, file))
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile(filepath.Join("testdata/unordered", file))
+			require.NoError(t, err)
+
+			require.Equal(t, string(expected), string(data))
+		}
+
+		// the original value is left untouched
+		require.Equal(t, []string{"c", "a", "b"}, actual.Tags)
+	})
+
+	t.Run("not a slice", func(t *testing.T) {
+		type test struct {
+			Tags string `testdata:"tags.json,unordered"`
+		}
+
+		var mt mockT
+		Assert(&mt, "testdata/unordered", &test{})
+
+		require.EqualValues(t, []string{
+			"[GoT] Assert: *got.test.Tags: unordered requires a slice, but got string",
+		}, mt.logs)
+	})
+}
@@ -0,0 +1,149 @@
+package got
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/fatih/structtag"
+)
+
+// The "utc" option converts every time.Time within a field to UTC, both when
+// loading and before comparing or saving with Assert, so a golden file stays
+// in UTC even when the code under test produces times in the local zone (eg:
+// `testdata:"event.json,utc"`). Times are still compared as instants, so this
+// only changes how they are represented.
+const utcOption = "utc"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// normalizeUTC returns a shallow copy of input (a pointer to a struct) where
+// the fields using the "utc" option are replaced with copies holding UTC times,
+// leaving the original untouched.
+func normalizeUTC(input any) (any, error) {
+	if input == nil || reflect.TypeOf(input).Kind() != reflect.Ptr || reflect.TypeOf(input).Elem().Kind() != reflect.Struct {
+		return input, nil // invalid inputs are reported elsewhere
+	}
+
+	typ := reflect.TypeOf(input).Elem()
+	output := reflect.New(typ)
+	output.Elem().Set(reflect.ValueOf(input).Elem())
+
+	err := walkFields(output.Interface(), func(field reflect.StructField, value reflect.Value, tag *structtag.Tag) error {
+		if !tag.HasOption(utcOption) {
+			return nil
+		}
+
+		if err := setUTC(field, value); err != nil {
+			return fmt.Errorf("%s.%s: %w", getTypeName(input), fieldName(field, tag), err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Interface(), nil
+}
+
+// setUTC converts every time.Time within value (the value of field) to UTC.
+func setUTC(field reflect.StructField, value reflect.Value) error {
+	if !hasTime(field.Type, make(map[reflect.Type]bool)) {
+		return fmt.Errorf("utc requires a type containing time.Time, but got %s", field.Type)
+	}
+
+	value.Set(utcValue(value))
+	return nil
+}
+
+// hasTime reports whether typ is or contains a time.Time which utcValue is
+// able to reach.
+func hasTime(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	if typ == timeType {
+		return true
+	}
+
+	if seen[typ] {
+		return false
+	}
+	seen[typ] = true
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		return true // the dynamic type is only known once loaded
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasTime(typ.Elem(), seen)
+	case reflect.Map:
+		return hasTime(typ.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if field := typ.Field(i); field.PkgPath == "" && hasTime(field.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// utcValue returns a copy of v where every time.Time (including those within
+// pointers, slices, arrays, maps, interfaces and exported struct fields) has
+// been converted to UTC.
+func utcValue(v reflect.Value) reflect.Value {
+	if v.Type() == timeType {
+		return reflect.ValueOf(v.Interface().(time.Time).UTC())
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(utcValue(v.Elem()))
+		return p
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		i := reflect.New(v.Type()).Elem()
+		i.Set(utcValue(v.Elem()))
+		return i
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(utcValue(v.Index(i)))
+		}
+		return s
+	case reflect.Array:
+		a := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			a.Index(i).Set(utcValue(v.Index(i)))
+		}
+		return a
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			m.SetMapIndex(key, utcValue(v.MapIndex(key)))
+		}
+		return m
+	case reflect.Struct:
+		s := reflect.New(v.Type()).Elem()
+		s.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				s.Field(i).Set(utcValue(v.Field(i)))
+			}
+		}
+		return s
+	}
+
+	return v
+}
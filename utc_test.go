@@ -0,0 +1,89 @@
+package got
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUTC(t *testing.T) {
+	type event struct {
+		At   time.Time  `json:"at"`
+		Prev *time.Time `json:"prev,omitempty"`
+	}
+
+	type test struct {
+		Event event `testdata:"event.json,utc"`
+	}
+
+	instant := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := instant.In(time.FixedZone("EST", -5*60*60))
+
+	t.Run("assert", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "event.json"), []byte(`{"at": "2024-01-02T03:04:05Z"}`), 0644))
+
+		var mt mockT
+		Assert(&mt, dir, &test{Event: event{At: local}})
+
+		require.False(t, mt.failed, mt.logs)
+	})
+
+	t.Run("save", func(t *testing.T) {
+		dir := t.TempDir()
+		prev := local.Add(-time.Hour)
+		actual := &test{Event: event{At: local, Prev: &prev}}
+
+		var mt mockT
+		SetUpdateGolden(true)
+		defer SetUpdateGolden(false)
+		Assert(&mt, dir, actual)
+		require.False(t, mt.failed, mt.logs)
+
+		data, err := os.ReadFile(filepath.Join(dir, "event.json"))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"at": "2024-01-02T03:04:05Z", "prev": "2024-01-02T02:04:05Z"}`, string(data))
+
+		// the original value is left untouched
+		require.Equal(t, "EST", actual.Event.At.Location().String())
+		require.Equal(t, "EST", actual.Event.Prev.Location().String())
+	})
+
+	t.Run("load", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "event.json"), []byte(`{"at": "2024-01-01T22:04:05-05:00"}`), 0644))
+
+		var mt mockT
+		var actual test
+		Load(&mt, dir, &actual)
+
+		require.False(t, mt.failed, mt.logs)
+		require.Equal(t, instant, actual.Event.At)
+		require.Equal(t, time.UTC, actual.Event.At.Location())
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		type test struct {
+			Events map[string][]event `testdata:"events.json,utc"`
+		}
+
+		normalized, err := normalizeUTC(&test{Events: map[string][]event{"a": {{At: local}}}})
+		require.NoError(t, err)
+		require.Equal(t, instant, normalized.(*test).Events["a"][0].At)
+	})
+
+	t.Run("no time", func(t *testing.T) {
+		type test struct {
+			Name string `testdata:"name.txt,utc"`
+		}
+
+		var mt mockT
+		Assert(&mt, t.TempDir(), &test{Name: "a"})
+
+		require.True(t, mt.failed)
+		require.Contains(t, mt.logs[len(mt.logs)-1], "*got.test.Name: utc requires a type containing time.Time, but got string")
+	})
+}